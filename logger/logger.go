@@ -88,6 +88,20 @@ func (l *logger) AddAttribute(key string, value interface{}) {
 	}
 }
 
+// WithAttribute returns a copy of the logger adding the given key-value
+// attribute to all messages logged through the copy. In contrast to
+// AddAttribute the original logger is not modified, so the copy can be used
+// for attributes only relevant to a single message.
+func (l *logger) WithAttribute(key string, value interface{}) telegraf.Logger {
+	derived := *l
+	derived.attributes = make(map[string]interface{}, len(l.attributes)+1)
+	for k, v := range l.attributes {
+		derived.attributes[k] = v
+	}
+	derived.AddAttribute(key, value)
+	return &derived
+}
+
 // Error logging including callbacks
 func (l *logger) Errorf(format string, args ...interface{}) {
 	l.Error(fmt.Sprintf(format, args...))
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -262,6 +263,68 @@ func TestStructuredDerivedLoggerWithAttributes(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestStructuredDerivedLoggerWithMessageAttribute(t *testing.T) {
+	instance = defaultHandler()
+
+	tmpfile, err := os.CreateTemp(t.TempDir(), "")
+	require.NoError(t, err)
+	defer os.Remove(tmpfile.Name())
+
+	filename := tmpfile.Name()
+	require.NoError(t, tmpfile.Close())
+
+	cfg := &Config{
+		Logfile:             filename,
+		LogFormat:           "structured",
+		RotationMaxArchives: -1,
+		Debug:               true,
+	}
+	require.NoError(t, SetupLogging(cfg))
+	defer func() { require.NoError(t, CloseLogging()) }()
+
+	l := New("testing", "test", "")
+	l.WithAttribute("category", "foo").Info("TEST") // Should be ignored
+	l.WithAttribute("device_id", 123).Info("TEST")
+	l.Info("TEST") // Should not contain the attribute
+
+	buf, err := os.ReadFile(filename)
+	require.NoError(t, err)
+
+	expected := []map[string]interface{}{
+		{
+			"level":    "INFO",
+			"msg":      "TEST",
+			"category": "testing",
+			"plugin":   "test",
+		},
+		{
+			"level":     "INFO",
+			"msg":       "TEST",
+			"category":  "testing",
+			"plugin":    "test",
+			"device_id": float64(123),
+		},
+		{
+			"level":    "INFO",
+			"msg":      "TEST",
+			"category": "testing",
+			"plugin":   "test",
+		},
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	require.Len(t, lines, len(expected))
+	for i, line := range lines {
+		var actual map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &actual))
+
+		require.Contains(t, actual, "time")
+		require.NotEmpty(t, actual["time"])
+		delete(actual, "time")
+		require.Equal(t, expected[i], actual)
+	}
+}
+
 func TestStructuredWriteToTruncatedFile(t *testing.T) {
 	tmpfile, err := os.CreateTemp(t.TempDir(), "")
 	require.NoError(t, err)
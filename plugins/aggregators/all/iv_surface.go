@@ -0,0 +1,5 @@
+//go:build !custom || aggregators || aggregators.iv_surface
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/aggregators/iv_surface" // register plugin
@@ -0,0 +1,5 @@
+//go:build !custom || aggregators || aggregators.trade_histogram
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/aggregators/trade_histogram" // register plugin
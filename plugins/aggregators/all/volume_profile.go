@@ -0,0 +1,5 @@
+//go:build !custom || aggregators || aggregators.volume_profile
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/aggregators/volume_profile" // register plugin
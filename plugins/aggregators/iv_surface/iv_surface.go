@@ -0,0 +1,218 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package iv_surface
+
+import (
+	_ "embed"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/aggregators"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+type IVSurface struct {
+	UnderlyingTag string          `toml:"underlying_tag"`
+	ExpiryTag     string          `toml:"expiry_tag"`
+	OptionTypeTag string          `toml:"option_type_tag"`
+	IVField       string          `toml:"iv_field"`
+	DeltaField    string          `toml:"delta_field"`
+	Log           telegraf.Logger `toml:"-"`
+
+	cache map[expiryKey]*expiry
+}
+
+// expiryKey identifies the options of an underlying with the same expiry
+type expiryKey struct {
+	name       string
+	underlying string
+	expiry     string
+}
+
+// expiry holds the latest quote of each option of the expiry
+type expiry struct {
+	calls map[uint64]quote
+	puts  map[uint64]quote
+}
+
+// quote of an option with the absolute delta
+type quote struct {
+	delta float64
+	iv    float64
+}
+
+func (*IVSurface) SampleConfig() string {
+	return sampleConfig
+}
+
+func (s *IVSurface) Init() error {
+	if s.UnderlyingTag == "" || s.ExpiryTag == "" || s.OptionTypeTag == "" {
+		return errors.New("underlying, expiry and option type tags required")
+	}
+	if s.IVField == "" || s.DeltaField == "" {
+		return errors.New("iv and delta fields required")
+	}
+	s.Reset()
+	return nil
+}
+
+func (s *IVSurface) Add(in telegraf.Metric) {
+	key := expiryKey{name: in.Name()}
+	var found bool
+	if key.underlying, found = in.GetTag(s.UnderlyingTag); !found {
+		return
+	}
+	if key.expiry, found = in.GetTag(s.ExpiryTag); !found {
+		return
+	}
+	optionType, found := in.GetTag(s.OptionTypeTag)
+	if !found {
+		return
+	}
+
+	rawIV, found := in.GetField(s.IVField)
+	if !found {
+		return
+	}
+	rawDelta, found := in.GetField(s.DeltaField)
+	if !found {
+		return
+	}
+	iv, ok := convert(rawIV)
+	if !ok {
+		return
+	}
+	delta, ok := convert(rawDelta)
+	if !ok {
+		return
+	}
+
+	e, found := s.cache[key]
+	if !found {
+		e = &expiry{
+			calls: make(map[uint64]quote),
+			puts:  make(map[uint64]quote),
+		}
+		s.cache[key] = e
+	}
+
+	// Keep the latest quote of each option series
+	q := quote{delta: math.Abs(delta), iv: iv}
+	switch strings.ToLower(optionType) {
+	case "call", "c":
+		e.calls[in.HashID()] = q
+	case "put", "p":
+		e.puts[in.HashID()] = q
+	default:
+		s.Log.Debugf("Ignoring option of unknown type %q", optionType)
+	}
+}
+
+func (s *IVSurface) Push(acc telegraf.Accumulator) {
+	for key, e := range s.cache {
+		calls, puts := sorted(e.calls), sorted(e.puts)
+		fields := map[string]interface{}{
+			"options": len(calls) + len(puts),
+		}
+
+		// The at-the-money volatility is taken at a delta of 0.5, averaging
+		// calls and puts if available.
+		var sum float64
+		var n int
+		if iv, ok := interpolate(calls, 0.5); ok {
+			sum += iv
+			n++
+		}
+		if iv, ok := interpolate(puts, 0.5); ok {
+			sum += iv
+			n++
+		}
+		atm := sum / float64(n)
+		if n > 0 {
+			fields["atm_iv"] = atm
+		}
+
+		call25, callOK := interpolate(calls, 0.25)
+		put25, putOK := interpolate(puts, 0.25)
+		if callOK {
+			fields["call_25d_iv"] = call25
+		}
+		if putOK {
+			fields["put_25d_iv"] = put25
+		}
+		if callOK && putOK {
+			fields["risk_reversal_25d"] = call25 - put25
+			if n > 0 {
+				fields["butterfly_25d"] = (call25+put25)/2 - atm
+			}
+		}
+
+		tags := map[string]string{
+			s.UnderlyingTag: key.underlying,
+			s.ExpiryTag:     key.expiry,
+		}
+		acc.AddFields(key.name+"_iv_surface", fields, tags)
+	}
+}
+
+func (s *IVSurface) Reset() {
+	s.cache = make(map[expiryKey]*expiry)
+}
+
+// sorted returns the quotes in ascending order of the delta
+func sorted(quotes map[uint64]quote) []quote {
+	list := make([]quote, 0, len(quotes))
+	for _, q := range quotes {
+		list = append(list, q)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].delta < list[j].delta })
+	return list
+}
+
+// interpolate the volatility at the given absolute delta linearly between the
+// neighboring quotes. Deltas outside the quoted range are not extrapolated.
+func interpolate(quotes []quote, delta float64) (float64, bool) {
+	idx := sort.Search(len(quotes), func(i int) bool { return quotes[i].delta >= delta })
+	if idx == len(quotes) {
+		return 0, false
+	}
+	upper := quotes[idx]
+	if upper.delta == delta {
+		return upper.iv, true
+	}
+	if idx == 0 {
+		return 0, false
+	}
+	lower := quotes[idx-1]
+	ratio := (delta - lower.delta) / (upper.delta - lower.delta)
+	return lower.iv + ratio*(upper.iv-lower.iv), true
+}
+
+func convert(in interface{}) (float64, bool) {
+	switch v := in.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func init() {
+	aggregators.Add("iv_surface", func() telegraf.Aggregator {
+		return &IVSurface{
+			UnderlyingTag: "underlying",
+			ExpiryTag:     "expiry",
+			OptionTypeTag: "option_type",
+			IVField:       "mark_iv",
+			DeltaField:    "delta",
+		}
+	})
+}
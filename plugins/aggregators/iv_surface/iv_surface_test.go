@@ -0,0 +1,109 @@
+package iv_surface
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func newPlugin() *IVSurface {
+	return &IVSurface{
+		UnderlyingTag: "underlying",
+		ExpiryTag:     "expiry",
+		OptionTypeTag: "option_type",
+		IVField:       "mark_iv",
+		DeltaField:    "delta",
+		Log:           testutil.Logger{},
+	}
+}
+
+func newOption(expiry, optionType string, strike int, delta, iv float64) telegraf.Metric {
+	return metric.New(
+		"option",
+		map[string]string{
+			"underlying":  "BTC",
+			"expiry":      expiry,
+			"option_type": optionType,
+			"instrument":  fmt.Sprintf("BTC-%s-%d-%s", expiry, strike, optionType),
+		},
+		map[string]interface{}{"mark_iv": iv, "delta": delta},
+		time.Unix(1741735124, 0),
+	)
+}
+
+func TestInitInvalid(t *testing.T) {
+	plugin := newPlugin()
+	plugin.ExpiryTag = ""
+	require.EqualError(t, plugin.Init(), "underlying, expiry and option type tags required")
+
+	plugin = newPlugin()
+	plugin.DeltaField = ""
+	require.EqualError(t, plugin.Init(), "iv and delta fields required")
+}
+
+func TestSurface(t *testing.T) {
+	plugin := newPlugin()
+	require.NoError(t, plugin.Init())
+
+	for _, m := range []telegraf.Metric{
+		newOption("250328", "C", 90000, 0.6, 49),
+		newOption("250328", "C", 95000, 0.5, 50),
+		newOption("250328", "C", 100000, 0.3, 56),
+		newOption("250328", "C", 105000, 0.2, 60),
+		newOption("250328", "P", 90000, -0.2, 66),
+		newOption("250328", "P", 95000, -0.3, 62),
+		newOption("250328", "P", 100000, -0.45, 53),
+		newOption("250328", "P", 105000, -0.55, 51),
+		// Only the at-the-money call is quoted for the other expiry
+		newOption("250425", "C", 95000, 0.52, 55),
+		newOption("250425", "C", 100000, 0.48, 57),
+	} {
+		plugin.Add(m)
+	}
+
+	// A newer quote replaces the previous one of the option
+	plugin.Add(newOption("250328", "C", 95000, 0.5, 50))
+
+	var acc testutil.Accumulator
+	plugin.Push(&acc)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"option_iv_surface",
+			map[string]string{"underlying": "BTC", "expiry": "250328"},
+			map[string]interface{}{
+				"options":           8,
+				"atm_iv":            51.0,
+				"call_25d_iv":       58.0,
+				"put_25d_iv":        64.0,
+				"risk_reversal_25d": -6.0,
+				"butterfly_25d":     10.0,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"option_iv_surface",
+			map[string]string{"underlying": "BTC", "expiry": "250425"},
+			map[string]interface{}{
+				"options": 2,
+				"atm_iv":  56.0,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(),
+		testutil.IgnoreTime(), testutil.SortMetrics(), cmpopts.EquateApprox(0, 1e-9))
+
+	// The surface starts over after a reset
+	plugin.Reset()
+	acc.ClearMetrics()
+	plugin.Push(&acc)
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
@@ -0,0 +1,161 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package trade_histogram
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/aggregators"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+type TradeHistogram struct {
+	Buckets       []float64 `toml:"buckets"`
+	PriceField    string    `toml:"price_field"`
+	QuantityField string    `toml:"quantity_field"`
+	NotionalField string    `toml:"notional_field"`
+
+	cache map[uint64]*histogram
+}
+
+// histogram of the trades of a series during the current period
+type histogram struct {
+	name     string
+	tags     map[string]string
+	counts   []int64
+	notional []float64
+}
+
+func (*TradeHistogram) SampleConfig() string {
+	return sampleConfig
+}
+
+func (h *TradeHistogram) Init() error {
+	if len(h.Buckets) == 0 {
+		return errors.New("no buckets configured")
+	}
+	for i, border := range h.Buckets {
+		if border <= 0 {
+			return fmt.Errorf("bucket border %v must be positive", border)
+		}
+		if i > 0 && border <= h.Buckets[i-1] {
+			return fmt.Errorf("bucket borders must be in ascending order, %v follows %v", border, h.Buckets[i-1])
+		}
+	}
+	if h.NotionalField == "" && (h.PriceField == "" || h.QuantityField == "") {
+		return errors.New("price and quantity fields required without notional field")
+	}
+
+	h.Reset()
+	return nil
+}
+
+func (h *TradeHistogram) Add(in telegraf.Metric) {
+	notional, ok := h.notional(in)
+	if !ok || notional < 0 {
+		return
+	}
+
+	id := in.HashID()
+	agg, found := h.cache[id]
+	if !found {
+		agg = &histogram{
+			name:     in.Name(),
+			tags:     in.Tags(),
+			counts:   make([]int64, len(h.Buckets)+1),
+			notional: make([]float64, len(h.Buckets)+1),
+		}
+		h.cache[id] = agg
+	}
+
+	// Trades at a bucket's border belong to that bucket
+	index := sort.SearchFloat64s(h.Buckets, notional)
+	agg.counts[index]++
+	agg.notional[index] += notional
+}
+
+// notional value of the trade in the metric, if the metric holds a trade
+func (h *TradeHistogram) notional(in telegraf.Metric) (float64, bool) {
+	if h.NotionalField != "" {
+		v, found := in.GetField(h.NotionalField)
+		if !found {
+			return 0, false
+		}
+		return convert(v)
+	}
+
+	pv, found := in.GetField(h.PriceField)
+	if !found {
+		return 0, false
+	}
+	qv, found := in.GetField(h.QuantityField)
+	if !found {
+		return 0, false
+	}
+	price, ok := convert(pv)
+	if !ok {
+		return 0, false
+	}
+	quantity, ok := convert(qv)
+	if !ok {
+		return 0, false
+	}
+	return price * quantity, true
+}
+
+func (h *TradeHistogram) Push(acc telegraf.Accumulator) {
+	for _, agg := range h.cache {
+		for i := range agg.counts {
+			tags := make(map[string]string, len(agg.tags)+2)
+			for k, v := range agg.tags {
+				tags[k] = v
+			}
+			tags["gt"] = "0"
+			if i > 0 {
+				tags["gt"] = strconv.FormatFloat(h.Buckets[i-1], 'f', -1, 64)
+			}
+			tags["le"] = "+Inf"
+			if i < len(h.Buckets) {
+				tags["le"] = strconv.FormatFloat(h.Buckets[i], 'f', -1, 64)
+			}
+
+			fields := map[string]interface{}{
+				"count":    agg.counts[i],
+				"notional": agg.notional[i],
+			}
+			acc.AddFields(agg.name+"_histogram", fields, tags)
+		}
+	}
+}
+
+func (h *TradeHistogram) Reset() {
+	h.cache = make(map[uint64]*histogram)
+}
+
+func convert(in interface{}) (float64, bool) {
+	switch v := in.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func init() {
+	aggregators.Add("trade_histogram", func() telegraf.Aggregator {
+		return &TradeHistogram{
+			PriceField:    "price",
+			QuantityField: "quantity",
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package trade_histogram
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func newTrade(side string, price, quantity float64) telegraf.Metric {
+	return metric.New(
+		"binance_trade",
+		map[string]string{"base": "BTC", "quote": "EUR", "side": side},
+		map[string]interface{}{
+			"price":          price,
+			"quantity":       quantity,
+			"quote_quantity": price * quantity,
+		},
+		time.Unix(1741735124, 0),
+	)
+}
+
+func TestInitInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugin  *TradeHistogram
+		message string
+	}{
+		{
+			name:    "no buckets",
+			plugin:  &TradeHistogram{PriceField: "price", QuantityField: "quantity"},
+			message: "no buckets configured",
+		},
+		{
+			name:    "unordered buckets",
+			plugin:  &TradeHistogram{Buckets: []float64{100, 10}, PriceField: "price", QuantityField: "quantity"},
+			message: "bucket borders must be in ascending order, 10 follows 100",
+		},
+		{
+			name:    "negative bucket",
+			plugin:  &TradeHistogram{Buckets: []float64{-1}, PriceField: "price", QuantityField: "quantity"},
+			message: "bucket border -1 must be positive",
+		},
+		{
+			name:    "missing quantity",
+			plugin:  &TradeHistogram{Buckets: []float64{10}, PriceField: "price"},
+			message: "price and quantity fields required without notional field",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.EqualError(t, tt.plugin.Init(), tt.message)
+		})
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	plugin := &TradeHistogram{
+		Buckets:       []float64{1000, 100000},
+		PriceField:    "price",
+		QuantityField: "quantity",
+	}
+	require.NoError(t, plugin.Init())
+
+	plugin.Add(newTrade("buy", 80000, 0.01))
+	plugin.Add(newTrade("buy", 80000, 0.0125))
+	plugin.Add(newTrade("buy", 80000, 0.5))
+	plugin.Add(newTrade("buy", 80000, 2))
+	plugin.Add(newTrade("sell", 80000, 0.5))
+
+	// Metrics without a trade are ignored
+	plugin.Add(metric.New("binance", map[string]string{"base": "BTC", "quote": "EUR"}, map[string]interface{}{"price": 80000.0}, time.Unix(0, 0)))
+
+	var acc testutil.Accumulator
+	plugin.Push(&acc)
+
+	buy := func(gt, le string) map[string]string {
+		return map[string]string{"base": "BTC", "quote": "EUR", "side": "buy", "gt": gt, "le": le}
+	}
+	sell := func(gt, le string) map[string]string {
+		return map[string]string{"base": "BTC", "quote": "EUR", "side": "sell", "gt": gt, "le": le}
+	}
+	expected := []telegraf.Metric{
+		metric.New("binance_trade_histogram", buy("0", "1000"), map[string]interface{}{"count": int64(2), "notional": 1800.0}, time.Unix(0, 0)),
+		metric.New("binance_trade_histogram", buy("1000", "100000"), map[string]interface{}{"count": int64(1), "notional": 40000.0}, time.Unix(0, 0)),
+		metric.New("binance_trade_histogram", buy("100000", "+Inf"), map[string]interface{}{"count": int64(1), "notional": 160000.0}, time.Unix(0, 0)),
+		metric.New("binance_trade_histogram", sell("0", "1000"), map[string]interface{}{"count": int64(0), "notional": 0.0}, time.Unix(0, 0)),
+		metric.New("binance_trade_histogram", sell("1000", "100000"), map[string]interface{}{"count": int64(1), "notional": 40000.0}, time.Unix(0, 0)),
+		metric.New("binance_trade_histogram", sell("100000", "+Inf"), map[string]interface{}{"count": int64(0), "notional": 0.0}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime(), testutil.SortMetrics())
+
+	// The histograms start over after a reset
+	plugin.Reset()
+	acc.ClearMetrics()
+	plugin.Push(&acc)
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
+
+func TestNotionalField(t *testing.T) {
+	plugin := &TradeHistogram{
+		Buckets:       []float64{1000},
+		NotionalField: "quote_quantity",
+	}
+	require.NoError(t, plugin.Init())
+
+	plugin.Add(newTrade("buy", 80000, 0.0125))
+	plugin.Add(newTrade("buy", 80000, 0.5))
+
+	var acc testutil.Accumulator
+	plugin.Push(&acc)
+
+	tags := func(gt, le string) map[string]string {
+		return map[string]string{"base": "BTC", "quote": "EUR", "side": "buy", "gt": gt, "le": le}
+	}
+	expected := []telegraf.Metric{
+		metric.New("binance_trade_histogram", tags("0", "1000"), map[string]interface{}{"count": int64(1), "notional": 1000.0}, time.Unix(0, 0)),
+		metric.New("binance_trade_histogram", tags("1000", "+Inf"), map[string]interface{}{"count": int64(1), "notional": 40000.0}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime(), testutil.SortMetrics())
+}
@@ -0,0 +1,157 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package volume_profile
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/aggregators"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+type VolumeProfile struct {
+	BucketSize  float64 `toml:"bucket_size"`
+	PriceField  string  `toml:"price_field"`
+	VolumeField string  `toml:"volume_field"`
+
+	// Number of decimals of the bucket size to format the bucket borders
+	decimals int
+	cache    map[uint64]*profile
+}
+
+// profile of the volume of a series during the current period
+type profile struct {
+	name    string
+	tags    map[string]string
+	buckets map[int64]*bucket
+}
+
+type bucket struct {
+	volume float64
+	trades int64
+}
+
+func (*VolumeProfile) SampleConfig() string {
+	return sampleConfig
+}
+
+func (v *VolumeProfile) Init() error {
+	if v.BucketSize <= 0 || math.IsInf(v.BucketSize, 0) || math.IsNaN(v.BucketSize) {
+		return fmt.Errorf("bucket_size %v must be positive", v.BucketSize)
+	}
+	if v.PriceField == "" || v.VolumeField == "" {
+		return errors.New("price and volume fields required")
+	}
+
+	if _, fraction, found := strings.Cut(strconv.FormatFloat(v.BucketSize, 'f', -1, 64), "."); found {
+		v.decimals = len(fraction)
+	}
+
+	v.Reset()
+	return nil
+}
+
+func (v *VolumeProfile) Add(in telegraf.Metric) {
+	pv, found := in.GetField(v.PriceField)
+	if !found {
+		return
+	}
+	vv, found := in.GetField(v.VolumeField)
+	if !found {
+		return
+	}
+	price, ok := convert(pv)
+	if !ok {
+		return
+	}
+	volume, ok := convert(vv)
+	if !ok {
+		return
+	}
+
+	id := in.HashID()
+	agg, found := v.cache[id]
+	if !found {
+		agg = &profile{
+			name:    in.Name(),
+			tags:    in.Tags(),
+			buckets: make(map[int64]*bucket),
+		}
+		v.cache[id] = agg
+	}
+
+	index := int64(math.Floor(price / v.BucketSize))
+	b, found := agg.buckets[index]
+	if !found {
+		b = &bucket{}
+		agg.buckets[index] = b
+	}
+	b.volume += volume
+	b.trades++
+}
+
+func (v *VolumeProfile) Push(acc telegraf.Accumulator) {
+	for _, agg := range v.cache {
+		// The bucket with the highest volume is the point of control
+		var poc int64
+		var maxVolume float64
+		var seen bool
+		for index, b := range agg.buckets {
+			if !seen || b.volume > maxVolume || (b.volume == maxVolume && index < poc) {
+				poc = index
+				maxVolume = b.volume
+				seen = true
+			}
+		}
+
+		for index, b := range agg.buckets {
+			price := float64(index) * v.BucketSize
+			tags := make(map[string]string, len(agg.tags)+1)
+			for key, value := range agg.tags {
+				tags[key] = value
+			}
+			tags["bucket"] = strconv.FormatFloat(price, 'f', v.decimals, 64)
+
+			fields := map[string]interface{}{
+				"price":            price,
+				"volume":           b.volume,
+				"trades":           b.trades,
+				"point_of_control": index == poc,
+			}
+			acc.AddFields(agg.name+"_volume_profile", fields, tags)
+		}
+	}
+}
+
+func (v *VolumeProfile) Reset() {
+	v.cache = make(map[uint64]*profile)
+}
+
+func convert(in interface{}) (float64, bool) {
+	switch v := in.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func init() {
+	aggregators.Add("volume_profile", func() telegraf.Aggregator {
+		return &VolumeProfile{
+			PriceField:  "price",
+			VolumeField: "quantity",
+		}
+	})
+}
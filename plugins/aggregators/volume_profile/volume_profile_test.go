@@ -0,0 +1,80 @@
+package volume_profile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func newTrade(price, quantity float64) telegraf.Metric {
+	return metric.New(
+		"binance_trade",
+		map[string]string{"base": "BTC", "quote": "EUR"},
+		map[string]interface{}{"price": price, "quantity": quantity},
+		time.Unix(1741735124, 0),
+	)
+}
+
+func TestInitInvalid(t *testing.T) {
+	plugin := &VolumeProfile{PriceField: "price", VolumeField: "quantity"}
+	require.EqualError(t, plugin.Init(), "bucket_size 0 must be positive")
+
+	plugin = &VolumeProfile{BucketSize: 10, PriceField: "price"}
+	require.EqualError(t, plugin.Init(), "price and volume fields required")
+}
+
+func TestProfile(t *testing.T) {
+	plugin := &VolumeProfile{
+		BucketSize:  0.5,
+		PriceField:  "price",
+		VolumeField: "quantity",
+	}
+	require.NoError(t, plugin.Init())
+
+	plugin.Add(newTrade(100.2, 1))
+	plugin.Add(newTrade(100.4, 2))
+	plugin.Add(newTrade(100.5, 0.5))
+	plugin.Add(newTrade(101.9, 1.5))
+
+	// Metrics without price or volume are ignored
+	plugin.Add(metric.New("binance", map[string]string{"base": "BTC", "quote": "EUR"}, map[string]interface{}{"price": 100.0}, time.Unix(0, 0)))
+
+	var acc testutil.Accumulator
+	plugin.Push(&acc)
+
+	tags := func(bucket string) map[string]string {
+		return map[string]string{"base": "BTC", "quote": "EUR", "bucket": bucket}
+	}
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_trade_volume_profile",
+			tags("100.0"),
+			map[string]interface{}{"price": 100.0, "volume": 3.0, "trades": int64(2), "point_of_control": true},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_trade_volume_profile",
+			tags("100.5"),
+			map[string]interface{}{"price": 100.5, "volume": 0.5, "trades": int64(1), "point_of_control": false},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_trade_volume_profile",
+			tags("101.5"),
+			map[string]interface{}{"price": 101.5, "volume": 1.5, "trades": int64(1), "point_of_control": false},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime(), testutil.SortMetrics())
+
+	// The profile starts over after a reset
+	plugin.Reset()
+	acc.ClearMetrics()
+	plugin.Push(&acc)
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
@@ -12,11 +12,17 @@ type Socks5ProxyConfig struct {
 }
 
 func (c *Socks5ProxyConfig) GetDialer() (proxy.Dialer, error) {
+	return c.GetForwardingDialer(proxy.Direct)
+}
+
+// GetForwardingDialer returns a dialer connecting to the SOCKS5 proxy using
+// the given forward dialer
+func (c *Socks5ProxyConfig) GetForwardingDialer(forward proxy.Dialer) (proxy.Dialer, error) {
 	var auth *proxy.Auth
 	if c.Socks5ProxyPassword != "" || c.Socks5ProxyUsername != "" {
 		auth = new(proxy.Auth)
 		auth.User = c.Socks5ProxyUsername
 		auth.Password = c.Socks5ProxyPassword
 	}
-	return proxy.SOCKS5("tcp", c.Socks5ProxyAddress, auth, proxy.Direct)
+	return proxy.SOCKS5("tcp", c.Socks5ProxyAddress, auth, forward)
 }
@@ -1,4 +1,4 @@
-//go:build !custom || inputs || inputs.bond
+//go:build !custom || inputs || inputs.binance
 
 package all
 
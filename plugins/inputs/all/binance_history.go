@@ -0,0 +1,5 @@
+//go:build !custom || inputs || inputs.binance_history
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/inputs/binance_history" // register plugin
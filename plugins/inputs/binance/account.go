@@ -0,0 +1,127 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	accountEndpoint string = "/account"
+	accountWeight   int64  = 20
+)
+
+type accountInfo struct {
+	Balances []struct {
+		Asset  string `json:"asset"`
+		Free   string `json:"free"`
+		Locked string `json:"locked"`
+	} `json:"balances"`
+}
+
+// balance of an asset in the spot account
+type balance struct {
+	asset  string
+	free   float64
+	locked float64
+}
+
+// balances queries the non-zero balances of the spot account
+func (b *Binance) balances(ctx context.Context) ([]balance, error) {
+	var info accountInfo
+	params := url.Values{"omitZeroBalances": {"true"}}
+	if err := b.querySigned(ctx, b.apiURL+accountEndpoint, params, accountWeight, &info); err != nil {
+		return nil, err
+	}
+
+	balances := make([]balance, 0, len(info.Balances))
+	for _, bal := range info.Balances {
+		free, err := strconv.ParseFloat(bal.Free, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse free balance %q of %s: %w", bal.Free, bal.Asset, err)
+		}
+		locked, err := strconv.ParseFloat(bal.Locked, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse locked balance %q of %s: %w", bal.Locked, bal.Asset, err)
+		}
+		balances = append(balances, balance{asset: bal.Asset, free: free, locked: locked})
+	}
+	return balances, nil
+}
+
+// gatherBalances emits the free and locked balance of each asset held in the
+// spot account, e.g. locked in open orders. With a valuation currency, the
+// balances and the whole portfolio are additionally valued in it.
+func (b *Binance) gatherBalances(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	balances, err := b.balances(ctx)
+	if err != nil {
+		return err
+	}
+
+	var prices map[string]float64
+	if b.PortfolioValuation != "" {
+		if prices, err = b.valuationPrices(ctx); err != nil {
+			acc.AddError(fmt.Errorf("querying prices for the portfolio valuation failed: %w", err))
+		}
+	}
+
+	var value float64
+	var valued, unvalued int
+	for _, bal := range balances {
+		total := bal.free + bal.locked
+		fields := map[string]interface{}{
+			"free":   bal.free,
+			"locked": bal.locked,
+			"total":  total,
+		}
+		tags := map[string]string{"asset": bal.asset}
+		if price, found := prices[bal.asset]; found {
+			fields["value"] = total * price
+			tags["valuation_quote"] = b.PortfolioValuation
+			value += total * price
+			valued++
+		} else if prices != nil {
+			b.Log.Debugf("No price of %s in %s, skipping it in the portfolio valuation", bal.asset, b.PortfolioValuation)
+			unvalued++
+		}
+		acc.AddFields("binance_balance", fields, tags)
+	}
+
+	if prices != nil {
+		fields := map[string]interface{}{
+			"value":           value,
+			"assets":          valued,
+			"unvalued_assets": unvalued,
+		}
+		acc.AddFields("binance_portfolio", fields, map[string]string{"valuation_quote": b.PortfolioValuation})
+	}
+	return nil
+}
+
+// valuationPrices returns the prices of all assets in the valuation currency,
+// either from the pairs with the valuation currency or via the USDT pairs of
+// both assets.
+func (b *Binance) valuationPrices(ctx context.Context) (map[string]float64, error) {
+	var ticks []tick
+	if err := b.query(ctx, priceEndpoint, nil, multiPriceWeight, &ticks); err != nil {
+		return nil, err
+	}
+
+	prices := pricesIn(ticks, b.PortfolioValuation)
+	usd := pricesIn(ticks, usdQuote)
+	if reference, found := usd[b.PortfolioValuation]; found {
+		for asset, price := range usd {
+			if _, found := prices[asset]; !found {
+				prices[asset] = price / reference
+			}
+		}
+	}
+	return prices, nil
+}
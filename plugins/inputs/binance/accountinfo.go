@@ -0,0 +1,44 @@
+package binance
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	accountInfoEndpoint string = "/account/info"
+	accountInfoWeight   int64  = 1
+)
+
+type accountStatus struct {
+	VipLevel                       int  `json:"vipLevel"`
+	IsMarginEnabled                bool `json:"isMarginEnabled"`
+	IsFutureEnabled                bool `json:"isFutureEnabled"`
+	IsOptionsEnabled               bool `json:"isOptionsEnabled"`
+	IsPortfolioMarginRetailEnabled bool `json:"isPortfolioMarginRetailEnabled"`
+}
+
+// gatherAccountInfo emits the VIP level of the account determining its fee
+// tier and the products enabled for it
+func (b *Binance) gatherAccountInfo(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var status accountStatus
+	if err := b.querySigned(ctx, b.sapiURL+accountInfoEndpoint, url.Values{}, accountInfoWeight, &status); err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{
+		"vip_level":                status.VipLevel,
+		"margin_enabled":           status.IsMarginEnabled,
+		"futures_enabled":          status.IsFutureEnabled,
+		"options_enabled":          status.IsOptionsEnabled,
+		"portfolio_margin_enabled": status.IsPortfolioMarginRetailEnabled,
+	}
+	acc.AddFields("binance_account", fields, nil)
+	return nil
+}
@@ -0,0 +1,131 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	aggTradesEndpoint string = "/aggTrades"
+	aggTradesWeight   int64  = 4
+	// Maximum number of aggregate trades returned per request
+	aggTradesLimit int = 1000
+)
+
+// aggTrade combines the trades of a taker order filled at the same price
+type aggTrade struct {
+	ID           int64  `json:"a"`
+	Price        string `json:"p"`
+	Qty          string `json:"q"`
+	FirstTradeID int64  `json:"f"`
+	LastTradeID  int64  `json:"l"`
+	Time         int64  `json:"T"`
+	IsBuyerMaker bool   `json:"m"`
+	// Decoded explicitly as it would otherwise be matched case-insensitively
+	// to the maker flag
+	IsBestMatch bool `json:"M"`
+}
+
+// gatherAggTrades emits the aggregate trades of the pairs since the last
+// reported one, either one metric per aggregate trade or a summary per pair.
+func (b *Binance) gatherAggTrades(acc telegraf.Accumulator) {
+	for _, p := range b.pairs {
+		acc.AddError(b.gatherPairAggTrades(acc, p))
+	}
+}
+
+func (b *Binance) gatherPairAggTrades(acc telegraf.Accumulator, p *pair) error {
+	params := p.query()
+	params.Set("limit", strconv.Itoa(aggTradesLimit))
+
+	var summary tradeSummary
+	var trades, firstID, lastID int64
+	for {
+		// Without a known last aggregate trade, start with the most recent ones
+		if id, found := b.state.LastAggTradeID[p.symbol]; found {
+			params.Set("fromId", strconv.FormatInt(id+1, 10))
+		}
+
+		page, err := b.fetchAggTrades(params)
+		if errors.Is(err, errBudgetExhausted) {
+			b.Log.Debugf("Continuing aggregate trades of %s in the next gather cycle: %v", p.symbol, err)
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, t := range page {
+			price, err := strconv.ParseFloat(t.Price, 64)
+			if err != nil {
+				return fmt.Errorf("cannot parse price %q of aggregate trade %d of %s: %w", t.Price, t.ID, p.symbol, err)
+			}
+			quantity, err := strconv.ParseFloat(t.Qty, 64)
+			if err != nil {
+				return fmt.Errorf("cannot parse quantity %q of aggregate trade %d of %s: %w", t.Qty, t.ID, p.symbol, err)
+			}
+
+			if b.AggTradesSummary {
+				summary.add(price, quantity, t.IsBuyerMaker)
+				trades += t.LastTradeID - t.FirstTradeID + 1
+			} else {
+				addAggTrade(acc, p, t, price, quantity)
+			}
+			if firstID == 0 {
+				firstID = t.ID
+			}
+			lastID = t.ID
+			b.state.LastAggTradeID[p.symbol] = t.ID
+		}
+
+		if len(page) < aggTradesLimit {
+			break
+		}
+	}
+
+	if b.AggTradesSummary {
+		fields := summary.fields()
+		fields["trades"] = trades
+		if summary.count > 0 {
+			fields["first_agg_trade_id"] = firstID
+			fields["last_agg_trade_id"] = lastID
+		}
+		acc.AddFields("binance_agg_trades", fields, p.tags)
+	}
+	return nil
+}
+
+func (b *Binance) fetchAggTrades(params url.Values) ([]aggTrade, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var trades []aggTrade
+	if err := b.query(ctx, aggTradesEndpoint, params, aggTradesWeight, &trades); err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+func addAggTrade(acc telegraf.Accumulator, p *pair, t aggTrade, price, quantity float64) {
+	fields := map[string]interface{}{
+		"id":             t.ID,
+		"price":          price,
+		"quantity":       quantity,
+		"quote_quantity": price * quantity,
+		"first_trade_id": t.FirstTradeID,
+		"last_trade_id":  t.LastTradeID,
+	}
+
+	// The taker sold if the buyer was the maker of the trade
+	side := "buy"
+	if t.IsBuyerMaker {
+		side = "sell"
+	}
+	acc.AddFields("binance_agg_trade", fields, p.tagsWith("side", side), time.UnixMilli(t.Time))
+}
@@ -0,0 +1,125 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const announcementsURLString string = "https://www.binance.com/bapi/composite/v1/public/cms/article/list/query"
+
+// Catalogs of the Binance announcements
+var announcementCatalogs = map[string]int{
+	"new_listing": 48,
+	"delisting":   161,
+}
+
+type announcementsResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+	Data    struct {
+		Catalogs []struct {
+			CatalogID int `json:"catalogId"`
+			Articles  []struct {
+				ID          int64  `json:"id"`
+				Code        string `json:"code"`
+				Title       string `json:"title"`
+				ReleaseDate int64  `json:"releaseDate"`
+			} `json:"articles"`
+		} `json:"catalogs"`
+	} `json:"data"`
+}
+
+var (
+	// Assets given in parentheses, e.g. "Binance Will List Pepe (PEPE)"
+	parenthesizedAssets = regexp.MustCompile(`\(([A-Z0-9]{2,})\)`)
+	// Assets listed in delisting titles, e.g. "Binance Will Delist ANT, MULTI on 2024-02-20"
+	delistedAssets = regexp.MustCompile(`Delist ((?:[A-Z0-9]{2,}(?:, | and |,))*[A-Z0-9]{2,}) on`)
+)
+
+// assetsFromTitle extracts the assets mentioned in an announcement title
+func assetsFromTitle(title string) []string {
+	var assets []string
+	for _, m := range parenthesizedAssets.FindAllStringSubmatch(title, -1) {
+		assets = append(assets, m[1])
+	}
+	if len(assets) > 0 {
+		return assets
+	}
+
+	if m := delistedAssets.FindStringSubmatch(title); m != nil {
+		list := strings.ReplaceAll(m[1], " and ", ",")
+		for _, a := range strings.Split(list, ",") {
+			if a = strings.TrimSpace(a); a != "" {
+				assets = append(assets, a)
+			}
+		}
+	}
+	return assets
+}
+
+// gatherAnnouncements emits the announcements released since the last query
+// in the configured categories.
+func (b *Binance) gatherAnnouncements(acc telegraf.Accumulator) {
+	if time.Since(b.announcementsQueried) < time.Duration(b.AnnouncementsRefresh) {
+		return
+	}
+	b.announcementsQueried = time.Now()
+
+	for _, category := range b.Announcements {
+		acc.AddError(b.gatherAnnouncementCategory(acc, category))
+	}
+}
+
+func (b *Binance) gatherAnnouncementCategory(acc telegraf.Accumulator, category string) error {
+	params := url.Values{
+		"type":      {"1"},
+		"catalogId": {strconv.Itoa(announcementCatalogs[category])},
+		"pageNo":    {"1"},
+		"pageSize":  {"20"},
+	}
+	address := withParams(b.announcementsURL, params)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+	var resp announcementsResponse
+	if err := b.fetch(ctx, address, &resp); err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("querying %s announcements failed: %s (code %s)", category, resp.Message, resp.Code)
+	}
+
+	last := b.state.LastAnnouncement[category]
+	newest := last
+	for _, catalog := range resp.Data.Catalogs {
+		for _, a := range catalog.Articles {
+			if a.ReleaseDate <= last {
+				continue
+			}
+			newest = max(newest, a.ReleaseDate)
+
+			fields := map[string]interface{}{
+				"id":     a.ID,
+				"code":   a.Code,
+				"title":  a.Title,
+				"assets": strings.Join(assetsFromTitle(a.Title), ","),
+			}
+			tags := map[string]string{"category": category}
+			acc.AddFields("binance_announcement", fields, tags, time.UnixMilli(a.ReleaseDate))
+
+			if b.SymbolWarnings && category == "delisting" {
+				b.addDelistingWarnings(acc, a.ID, a.Title, time.UnixMilli(a.ReleaseDate))
+			}
+		}
+	}
+	b.state.LastAnnouncement[category] = newest
+	return nil
+}
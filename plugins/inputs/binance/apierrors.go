@@ -0,0 +1,92 @@
+package binance
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// Categories of common Binance error codes, see
+// https://developers.binance.com/docs/binance-spot-api-docs/errors
+var errorCategories = map[int]string{
+	-1000: "unknown",
+	-1001: "disconnected",
+	-1002: "unauthorized",
+	-1003: "rate_limit",
+	-1006: "unexpected_response",
+	-1007: "timeout",
+	-1008: "server_busy",
+	-1021: "invalid_timestamp",
+	-1022: "invalid_signature",
+	-1100: "invalid_parameter",
+	-1101: "invalid_parameter",
+	-1102: "invalid_parameter",
+	-1103: "invalid_parameter",
+	-1104: "invalid_parameter",
+	-1105: "invalid_parameter",
+	-1106: "invalid_parameter",
+	-1121: "invalid_symbol",
+	-1122: "invalid_symbol_status",
+	-1130: "invalid_parameter",
+	-2014: "invalid_api_key",
+	-2015: "invalid_api_key",
+}
+
+// errorCategory returns a stable category for the given error code
+func errorCategory(code int) string {
+	if category, found := errorCategories[code]; found {
+		return category
+	}
+	switch {
+	case code <= -1000 && code > -1100:
+		return "server"
+	case code <= -1100 && code > -2000:
+		return "invalid_request"
+	case code <= -2000 && code > -3000:
+		return "rejected"
+	}
+	return "unknown"
+}
+
+// failedRequest records an error response of Binance
+type failedRequest struct {
+	endpoint   string
+	statusCode int
+	err        *apiError
+	ts         time.Time
+}
+
+// addAPIErrors emits the error responses received since the last call
+func (b *Binance) addAPIErrors(acc telegraf.Accumulator) {
+	for _, f := range b.failedRequests {
+		tags := map[string]string{
+			"endpoint": f.endpoint,
+			"code":     strconv.Itoa(f.err.Code),
+			"category": f.err.category(),
+		}
+		fields := map[string]interface{}{
+			"message":     f.err.Msg,
+			"status_code": f.statusCode,
+		}
+		acc.AddFields("binance_api_error", fields, tags, f.ts)
+	}
+	b.failedRequests = b.failedRequests[:0]
+}
+
+// attributeLogger is implemented by loggers able to attach attributes to
+// single messages without modifying the plugin's logger
+type attributeLogger interface {
+	WithAttribute(key string, value interface{}) telegraf.Logger
+}
+
+// logAPIError logs the error response with its category as structured
+// attribute. The key "category" is reserved for the plugin category by
+// Telegraf's logger, so the attribute is named "error_category".
+func (b *Binance) logAPIError(endpoint string, err *apiError) {
+	log := b.Log
+	if l, ok := log.(attributeLogger); ok {
+		log = l.WithAttribute("error_category", err.category())
+	}
+	log.Warnf("Request to %s failed: %v", endpoint, err)
+}
@@ -0,0 +1,183 @@
+package binance
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// auditRecord is a line of the audit log of authenticated requests. Each record
+// contains the keyed hash of its predecessor to make modifications detectable
+// by anyone knowing the key.
+type auditRecord struct {
+	Time       string            `json:"time"`
+	Method     string            `json:"method"`
+	Host       string            `json:"host"`
+	Endpoint   string            `json:"endpoint"`
+	Params     map[string]string `json:"params,omitempty"`
+	StatusCode int               `json:"status_code"`
+	ErrorCode  int               `json:"error_code,omitempty"`
+	PrevHash   string            `json:"prev_hash"`
+	Hash       string            `json:"hash,omitempty"`
+}
+
+// hash returns the HMAC-SHA256 digest of the JSON-encoded record without its
+// hash using the given key
+func (r auditRecord) hash(key []byte) (string, error) {
+	r.Hash = ""
+	buf, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(buf)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// verifyAuditLog checks the chain of the records read from the given audit log
+// using the key and returns the hash of the last record. Removing records from
+// the end of the log cannot be detected here.
+func verifyAuditLog(r io.Reader, key []byte) (string, error) {
+	var prev string
+	var line int
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record auditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return "", fmt.Errorf("parsing record in line %d failed: %w", line, err)
+		}
+		if record.PrevHash != prev {
+			return "", fmt.Errorf("record in line %d does not continue the chain", line)
+		}
+		hash, err := record.hash(key)
+		if err != nil {
+			return "", fmt.Errorf("hashing record in line %d failed: %w", line, err)
+		}
+		if !hmac.Equal([]byte(hash), []byte(record.Hash)) {
+			return "", fmt.Errorf("record in line %d has been modified", line)
+		}
+		prev = record.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return prev, nil
+}
+
+// loadAuditHash verifies an existing audit log and returns the hash of its
+// last record to continue the chain.
+func (b *Binance) loadAuditHash() (string, error) {
+	f, err := os.Open(b.AuditLog)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading audit log failed: %w", err)
+	}
+	defer f.Close()
+
+	key, err := b.AuditKey.Get()
+	if err != nil {
+		return "", fmt.Errorf("getting audit key failed: %w", err)
+	}
+	defer key.Destroy()
+
+	hash, err := verifyAuditLog(f, key.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("verifying audit log %q failed: %w", b.AuditLog, err)
+	}
+	return hash, nil
+}
+
+// openAuditLog opens the audit log for appending records, continuing the
+// chain of an existing log.
+func (b *Binance) openAuditLog() error {
+	hash, err := b.loadAuditHash()
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(b.AuditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening audit log failed: %w", err)
+	}
+	b.auditHash = hash
+	b.auditFile = f
+	return nil
+}
+
+// closeAuditLog closes the audit log if open
+func (b *Binance) closeAuditLog() {
+	if b.auditFile == nil {
+		return
+	}
+	if err := b.auditFile.Close(); err != nil {
+		b.Log.Errorf("Closing audit log failed: %v", err)
+	}
+	b.auditFile = nil
+}
+
+// audit appends the record of an authenticated request to the audit log.
+// The signature is not part of the parameters and the API key is sent as
+// header, so neither ends up in the log.
+func (b *Binance) audit(r *http.Request, statusCode int, err error) {
+	if b.auditFile == nil {
+		return
+	}
+
+	record := auditRecord{
+		Time:       time.Now().UTC().Format(time.RFC3339Nano),
+		Method:     r.Method,
+		Host:       r.URL.Host,
+		Endpoint:   r.URL.Path,
+		StatusCode: statusCode,
+		PrevHash:   b.auditHash,
+	}
+	query := r.URL.Query()
+	query.Del("signature")
+	if len(query) > 0 {
+		record.Params = make(map[string]string, len(query))
+		for name := range query {
+			record.Params[name] = query.Get(name)
+		}
+	}
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		record.ErrorCode = apiErr.Code
+	}
+
+	key, err := b.AuditKey.Get()
+	if err != nil {
+		b.Log.Errorf("Getting audit key failed: %v", err)
+		return
+	}
+	hash, err := record.hash(key.Bytes())
+	key.Destroy()
+	if err != nil {
+		b.Log.Errorf("Encoding audit record failed: %v", err)
+		return
+	}
+	record.Hash = hash
+	buf, err := json.Marshal(record)
+	if err != nil {
+		b.Log.Errorf("Encoding audit record failed: %v", err)
+		return
+	}
+	if _, err := b.auditFile.Write(append(buf, '\n')); err != nil {
+		b.Log.Errorf("Writing audit log failed: %v", err)
+		return
+	}
+	b.auditHash = hash
+}
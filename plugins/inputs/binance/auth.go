@@ -0,0 +1,173 @@
+package binance
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Methods for signing requests, depending on the type of the API key
+const (
+	signatureHMAC    = "hmac"
+	signatureEd25519 = "ed25519"
+	signatureRSA     = "rsa"
+)
+
+// loadPrivateKey reads the PEM-encoded private key of an Ed25519 or RSA API
+// key from the given file and checks it against the signature method.
+func loadPrivateKey(filename, method string) (crypto.Signer, error) {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading private key failed: %w", err)
+	}
+	block, _ := pem.Decode(buf)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM-encoded private key found in %q", filename)
+	}
+
+	var key interface{}
+	if block.Type == "RSA PRIVATE KEY" {
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key in %q failed: %w", filename, err)
+	}
+
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		if method == signatureEd25519 {
+			return k, nil
+		}
+	case *rsa.PrivateKey:
+		if method == signatureRSA {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("private key in %q does not match signature method %q", filename, method)
+}
+
+// canSign checks if the credentials for signing requests are configured
+func (b *Binance) canSign() bool {
+	if b.APIKey.Empty() {
+		return false
+	}
+	return b.privateKey != nil || !b.APISecret.Empty()
+}
+
+// setAPIKey adds the API key to the request if configured
+func (b *Binance) setAPIKey(r *http.Request) error {
+	if b.APIKey.Empty() {
+		return nil
+	}
+	key, err := b.APIKey.Get()
+	if err != nil {
+		return fmt.Errorf("getting API key failed: %w", err)
+	}
+	r.Header.Set("X-MBX-APIKEY", key.String())
+	key.Destroy()
+	return nil
+}
+
+// doAuthenticated is the equivalent of do for requests authenticated by the
+// API key, recording them in the audit log.
+func (b *Binance) doAuthenticated(r *http.Request, v interface{}) error {
+	if err := b.setAPIKey(r); err != nil {
+		return err
+	}
+	statusCode, err := b.send(r, v)
+	if r.Header.Get("X-MBX-APIKEY") != "" {
+		b.audit(r, statusCode, err)
+	}
+	return err
+}
+
+// signedQuery returns the query string of the parameters with the timestamp
+// and the signature appended as required by endpoints with security type
+// USER_DATA. The signature must be the last parameter.
+func (b *Binance) signedQuery(params url.Values, now time.Time) (string, error) {
+	params.Set("timestamp", strconv.FormatInt(now.UnixMilli(), 10))
+	params.Set("recvWindow", strconv.FormatInt(time.Duration(b.RecvWindow).Milliseconds(), 10))
+	query := params.Encode()
+
+	// Ed25519 and RSA keys sign the query itself respectively its SHA-256
+	// digest, with the signature encoded in base64
+	switch key := b.privateKey.(type) {
+	case ed25519.PrivateKey:
+		signature := ed25519.Sign(key, []byte(query))
+		return query + "&signature=" + url.QueryEscape(base64.StdEncoding.EncodeToString(signature)), nil
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(query))
+		signature, err := rsa.SignPKCS1v15(nil, key, crypto.SHA256, digest[:])
+		if err != nil {
+			return "", fmt.Errorf("signing request failed: %w", err)
+		}
+		return query + "&signature=" + url.QueryEscape(base64.StdEncoding.EncodeToString(signature)), nil
+	}
+
+	secret, err := b.APISecret.Get()
+	if err != nil {
+		return "", fmt.Errorf("getting API secret failed: %w", err)
+	}
+	defer secret.Destroy()
+
+	mac := hmac.New(sha256.New, secret.Bytes())
+	mac.Write([]byte(query))
+	return query + "&signature=" + hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// querySigned is the equivalent of queryURL for endpoints requiring a signed
+// request authenticated by the API key.
+func (b *Binance) querySigned(ctx context.Context, base string, params url.Values, weight int64, v interface{}) error {
+	return b.requestSigned(ctx, http.MethodGet, base, params, weight, v)
+}
+
+// postSigned sends a signed POST request with the parameters in the query
+// string, e.g. to request a quote without placing an order.
+func (b *Binance) postSigned(ctx context.Context, base string, params url.Values, weight int64, v interface{}) error {
+	return b.requestSigned(ctx, http.MethodPost, base, params, weight, v)
+}
+
+func (b *Binance) requestSigned(ctx context.Context, method, base string, params url.Values, weight int64, v interface{}) error {
+	if !b.canSign() {
+		return errors.New("signed requests require api_key and api_secret or private_key_file to be set")
+	}
+	// Continue with the previous offset if the server time is not available
+	if err := b.syncServerTime(ctx); err != nil {
+		b.Log.Warnf("Synchronizing with the server time failed: %v", err)
+	}
+	if err := b.budget.reserve(time.Now(), weight); err != nil {
+		return fmt.Errorf("skipping request to %s: %w", base, err)
+	}
+
+	query, err := b.signedQuery(params, time.Now().Add(b.serverTimeOffset))
+	if err != nil {
+		return err
+	}
+	r, err := newRequest(ctx, method, base+"?"+query, nil)
+	if err != nil {
+		return err
+	}
+	err = b.doAuthenticated(r, v)
+	// Resynchronize with the next signed request if the clock drifted
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.Code == codeInvalidTimestamp {
+		b.serverTimeSynced = time.Time{}
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package binance
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// isBackOffStatus returns true for responses requesting to back off, i.e.
+// 429 for exceeding the rate limit and 418 for an IP address banned for
+// repeatedly doing so
+func isBackOffStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusTeapot
+}
+
+// backOff blocks all requests for the time given in the Retry-After header of
+// the response or for one rate-limit period if the header is missing.
+func (b *Binance) backOff(resp *http.Response) {
+	retryAfter := rateLimitPeriod
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			b.Log.Debugf("Cannot parse Retry-After header %q: %v", value, err)
+		} else {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+
+	until := time.Now().Add(retryAfter)
+	b.budget.block(until, resp.StatusCode)
+	b.Log.Warnf("Binance responded with status %d, backing off until %s", resp.StatusCode, until.Format(time.RFC3339))
+}
+
+// addBackOff emits the current back-off, if any
+func (b *Binance) addBackOff(acc telegraf.Accumulator) {
+	now := time.Now()
+	until, statusCode := b.budget.blocked(now)
+	if until.IsZero() {
+		return
+	}
+	fields := map[string]interface{}{
+		"status_code":   statusCode,
+		"banned":        statusCode == http.StatusTeapot,
+		"blocked_until": until.UnixMilli(),
+		"remaining":     until.Sub(now).Seconds(),
+	}
+	acc.AddFields("binance_back_off", fields, nil)
+}
@@ -0,0 +1,60 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// spotTicks queries the prices of all symbols on the spot market to compute
+// the basis of the futures contracts. The spot symbols of the contracts are
+// not necessarily listed, so all symbols are queried at the same weight as
+// multiple ones.
+func (b *Binance) spotTicks(ctx context.Context) (map[string]tick, error) {
+	var ticks []tick
+	if err := b.queryURL(ctx, b.spotURL+priceEndpoint, nil, multiPriceWeight, &ticks); err != nil {
+		return nil, err
+	}
+	result := make(map[string]tick, len(ticks))
+	for _, t := range ticks {
+		result[t.Symbol] = t
+	}
+	return result, nil
+}
+
+// spotSymbol returns the spot symbol of the pair's contract. COIN-M contracts
+// are quoted in US dollars, so they are compared to the USDT pair.
+func (p *pair) spotSymbol() string {
+	quote := p.tags["quote"]
+	if quote == "USD" {
+		quote = usdQuote
+	}
+	return p.tags["base"] + quote
+}
+
+// addBasis adds the difference of the mark price to the spot price to the
+// fields, both absolute and relative to the spot price in percent.
+func addBasis(fields map[string]interface{}, p *pair, spot map[string]tick) error {
+	mark, found := fields["mark_price"].(float64)
+	if !found {
+		return nil
+	}
+	symbol := p.spotSymbol()
+	t, found := spot[symbol]
+	if !found {
+		return fmt.Errorf("no spot price received for symbol %s to compute the basis of %s", symbol, p.symbol)
+	}
+	price, err := strconv.ParseFloat(strings.TrimSpace(t.Price), 64)
+	if err != nil {
+		return fmt.Errorf("cannot parse price %s of symbol %s: %w", t.Price, symbol, err)
+	}
+	price = p.price(price)
+	if price == 0 {
+		return nil
+	}
+	fields["spot_price"] = price
+	fields["basis"] = mark - price
+	fields["basis_percent"] = (mark - price) / price * 100
+	return nil
+}
@@ -3,32 +3,59 @@ package binance
 
 import (
 	"context"
+	"crypto"
 	_ "embed"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
 	"net/url"
+	"os"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/common/proxy"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
 
 const (
-	baseApiUrlString      string = "https://api.binance.com/api/v3"
-	priceUrlString        string = baseApiUrlString + "/ticker/price"
-	exchangeInfoUrlString string = baseApiUrlString + "/exchangeInfo"
+	baseApiUrlString string = "https://api.binance.com/api/v3"
+	futuresURLString string = "https://fapi.binance.com/fapi/v1"
+	priceEndpoint    string = "/ticker/price"
+	exchangeEndpoint string = "/exchangeInfo"
 )
 
-type payload struct {
+// Request weights as documented by Binance
+const (
+	priceWeight        int64 = 2
+	multiPriceWeight   int64 = 4
+	exchangeInfoWeight int64 = 20
+)
+
+// apiError is the error payload returned by Binance
+type apiError struct {
 	Code int    `json:"code"`
 	Msg  string `json:"msg"`
 }
 
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s (code %d, %s)", e.Msg, e.Code, e.category())
+}
+
+func (e *apiError) category() string {
+	return errorCategory(e.Code)
+}
+
 type tick struct {
 	Symbol string `json:"symbol"`
 	Price  string `json:"price"`
@@ -38,7 +65,7 @@ type tick struct {
 var sampleConfig string
 
 var (
-	header map[string][]string = map[string][]string{
+	header http.Header = map[string][]string{
 		"User-Agent":   {"Telegraf"},
 		"Accept":       {"application/json"},
 		"Content-Type": {"application/json"},
@@ -46,14 +73,167 @@ var (
 )
 
 type Binance struct {
-	BaseAsset       string          `toml:"base_asset"`
-	QuoteAsset      string          `toml:"quote_asset"`
-	Timeout         config.Duration `toml:"timeout"`
-	Log             telegraf.Logger `toml:"-"`
-	tags            map[string]string
-	client          *http.Client
-	priceURL        *url.URL
-	exchangeInfoURL *url.URL
+	Market               string          `toml:"market"`
+	Symbol               string          `toml:"symbol"`
+	Symbols              []string        `toml:"symbols"`
+	SymbolsFile          string          `toml:"symbols_file"`
+	AllSymbols           bool            `toml:"all_symbols"`
+	TopSymbolsByVolume   int             `toml:"top_symbols_by_volume"`
+	SymbolInclude        []string        `toml:"symbol_include"`
+	SymbolExclude        []string        `toml:"symbol_exclude"`
+	SymbolsRefresh       config.Duration `toml:"symbols_refresh"`
+	BaseAsset            string          `toml:"base_asset"`
+	QuoteAsset           string          `toml:"quote_asset"`
+	QuoteAssets          []string        `toml:"quote_assets"`
+	Timeout              config.Duration `toml:"timeout"`
+	LocalAddress         string          `toml:"local_address"`
+	IPVersion            string          `toml:"ip_version"`
+	DNSCacheTTL          config.Duration `toml:"dns_cache_ttl"`
+	ForceHTTP1           bool            `toml:"force_http1"`
+	SystemStatus         bool            `toml:"system_status"`
+	ProbeEndpoints       bool            `toml:"probe_endpoints"`
+	ProbeInterval        config.Duration `toml:"probe_interval"`
+	MarginAssets         []string        `toml:"margin_assets"`
+	MarginIsolated       bool            `toml:"margin_isolated"`
+	MarginMaxBorrowable  bool            `toml:"margin_max_borrowable"`
+	MarginVipLevel       int             `toml:"margin_vip_level"`
+	LeverageBrackets     []string        `toml:"leverage_brackets"`
+	FundingRates         bool            `toml:"funding_rates"`
+	Basis                bool            `toml:"basis"`
+	OpenInterest         bool            `toml:"open_interest"`
+	OpenInterestPeriod   string          `toml:"open_interest_period"`
+	SentimentPeriod      string          `toml:"sentiment_period"`
+	FuturesPositions     bool            `toml:"futures_positions"`
+	Liquidations         bool            `toml:"liquidations"`
+	SymbolWarnings       bool            `toml:"symbol_warnings"`
+	SymbolStatusRefresh  config.Duration `toml:"symbol_status_refresh"`
+	StatusTag            bool            `toml:"status_tag"`
+	SymbolFilters        bool            `toml:"symbol_filters"`
+	ExchangeInfoSummary  bool            `toml:"exchange_info_summary"`
+	ExchangeInfoRefresh  config.Duration `toml:"exchange_info_refresh"`
+	RateLimitGroup       string          `toml:"rate_limit_group"`
+	RateLimitWeight      int64           `toml:"rate_limit_weight"`
+	RateLimitUsage       bool            `toml:"rate_limit_usage"`
+	APIKey               config.Secret   `toml:"api_key"`
+	APISecret            config.Secret   `toml:"api_secret"`
+	SignatureMethod      string          `toml:"signature_method"`
+	PrivateKeyFile       string          `toml:"private_key_file"`
+	RecvWindow           config.Duration `toml:"recv_window"`
+	ServerTimeRefresh    config.Duration `toml:"server_time_refresh"`
+	AccountBalances      bool            `toml:"account_balances"`
+	AccountInfo          bool            `toml:"account_info"`
+	AccountSnapshots     []string        `toml:"account_snapshots"`
+	PortfolioValuation   string          `toml:"portfolio_valuation"`
+	OpenOrders           bool            `toml:"open_orders"`
+	MyTrades             bool            `toml:"my_trades"`
+	MyTradesSummary      bool            `toml:"my_trades_summary"`
+	TradeFees            bool            `toml:"trade_fees"`
+	CommissionRates      bool            `toml:"commission_rates"`
+	DepositHistory       bool            `toml:"deposit_history"`
+	WithdrawalHistory    bool            `toml:"withdrawal_history"`
+	MarginAccount        bool            `toml:"margin_account"`
+	IsolatedAccount      bool            `toml:"margin_isolated_account"`
+	CryptoLoans          bool            `toml:"crypto_loans"`
+	SimpleEarn           bool            `toml:"simple_earn"`
+	Staking              []string        `toml:"staking"`
+	SubAccounts          bool            `toml:"sub_accounts"`
+	KeyPermissions       bool            `toml:"api_key_permissions"`
+	ExpectedPermissions  []string        `toml:"expected_permissions"`
+	PermissionsRefresh   config.Duration `toml:"permissions_refresh"`
+	ExportStart          string          `toml:"export_start"`
+	ExportEnd            string          `toml:"export_end"`
+	ExportInterval       string          `toml:"export_interval"`
+	BackfillDuration     config.Duration `toml:"backfill_duration"`
+	KlineInterval        string          `toml:"kline_interval"`
+	KlineTimestamp       string          `toml:"kline_timestamp"`
+	GapFill              bool            `toml:"gap_fill"`
+	GapFillThreshold     config.Duration `toml:"gap_fill_threshold"`
+	GapFillMaxAge        config.Duration `toml:"gap_fill_max_age"`
+	P2P                  []*p2pMarket    `toml:"p2p"`
+	ConvertQuotes        []*convertQuote `toml:"convert_quote"`
+	Announcements        []string        `toml:"announcements"`
+	AnnouncementsRefresh config.Duration `toml:"announcements_refresh"`
+	IndexInfo            []string        `toml:"index_info"`
+	Collect24hStats      bool            `toml:"collect_24h_stats"`
+	NotionalUSD          bool            `toml:"notional_usd"`
+	RollingWindows       []string        `toml:"rolling_windows"`
+	TradingDay           bool            `toml:"trading_day"`
+	TradingDayTimezone   string          `toml:"trading_day_timezone"`
+	HistoricalTrades     bool            `toml:"historical_trades"`
+	RecentTrades         int             `toml:"recent_trades"`
+	AggTrades            bool            `toml:"agg_trades"`
+	AggTradesSummary     bool            `toml:"agg_trades_summary"`
+	Depth                bool            `toml:"depth"`
+	DepthLimit           int             `toml:"depth_limit"`
+	DepthLayout          string          `toml:"depth_layout"`
+	DepthBps             []int           `toml:"depth_bps"`
+	DepthPressureLevels  int             `toml:"depth_pressure_levels"`
+	SlippageOrderSizes   []float64       `toml:"slippage_order_sizes"`
+	AvgPrice             bool            `toml:"avg_price"`
+	PriceBands           bool            `toml:"price_bands"`
+	ConvertTo            string          `toml:"convert_to"`
+	HTTPTiming           bool            `toml:"http_timing"`
+	TracingEndpoint      string          `toml:"tracing_endpoint"`
+	AuditLog             string          `toml:"audit_log"`
+	AuditKey             config.Secret   `toml:"audit_key"`
+	DebugHTTP            bool            `toml:"debug_http"`
+	Log                  telegraf.Logger `toml:"-"`
+	proxy.HTTPProxy
+	proxy.Socks5ProxyConfig
+	pairs                []*pair
+	dynamic              bool
+	privateKey           crypto.Signer
+	serverTimeOffset     time.Duration
+	serverTimeSynced     time.Time
+	fileSymbols          []string
+	quotes               []string
+	symbolInclude        []*regexp.Regexp
+	symbolExclude        []*regexp.Regexp
+	client               *http.Client
+	apiURL               string
+	spotURL              string
+	p2pURL               string
+	announcementsURL     string
+	futuresURL           string
+	coinmURL             string
+	futuresDataURL       string
+	futuresV2URL         string
+	streamURL            string
+	sapiURL              string
+	sapiV2URL            string
+	sapiV4URL            string
+	budget               *weightBudget
+	exportStart          time.Time
+	exportEnd            time.Time
+	exported             bool
+	state                state
+	announcementsQueried time.Time
+	statusQueried        time.Time
+	exchangeInfoQueried  time.Time
+	permissionsQueried   time.Time
+	snapshotsQueried     time.Time
+	pairsQueried         time.Time
+	indexCompositions    map[string]string
+	leverageBracketsSeen map[string]string
+	loanCollateral       map[string]map[string]interface{}
+	collateralQueried    time.Time
+	futuresDataSeen      map[string]int64
+	fundingDue           map[string]int64
+	conversions          map[string]conversion
+	timings              []*requestTiming
+	dnsCache             *dnsCache
+	failedRequests       []failedRequest
+	usedWeights          map[string]int64
+	probeURLs            map[string]string
+	probed               time.Time
+	spanExporter         sdktrace.SpanExporter
+	tracerProvider       *sdktrace.TracerProvider
+	tracer               trace.Tracer
+	traceCtx             context.Context
+	auditHash            string
+	auditFile            *os.File
+	cancelStream         context.CancelFunc
+	streamDone           chan struct{}
 }
 
 // SampleConfig returns the sample configuration for the plugin.
@@ -66,110 +246,892 @@ func (b *Binance) Init() error {
 	b.Log.Trace("Initializing Btc plugin")
 
 	b.Log.Trace("Validating configuration")
-	if b.BaseAsset == "" || b.QuoteAsset == "" {
-		return errors.New("base_asset and quote_asset cannot be empty")
+	hasQuotes := b.QuoteAsset != "" || len(b.QuoteAssets) > 0
+	// Without a base asset, the quote assets select all pairs quoted in them
+	quoteSelection := b.BaseAsset == "" && hasQuotes
+	if quoteSelection && (b.Symbol != "" || len(b.Symbols) > 0) {
+		return errors.New("quote_asset and quote_assets require base_asset to be set if combined with symbol or symbols")
+	}
+	if b.AllSymbols && (b.Symbol != "" || len(b.Symbols) > 0 || b.BaseAsset != "") {
+		return errors.New("all_symbols cannot be combined with symbol, symbols or base_asset")
+	}
+	switch b.Market {
+	case "":
+		b.Market = marketSpot
+	case marketSpot, marketUSDMFutures, marketCOINMFutures:
+	default:
+		return fmt.Errorf("invalid market %q", b.Market)
+	}
+	if options := b.spotOnlyOptions(); b.futures() && len(options) > 0 {
+		return fmt.Errorf("%s not supported on the %s market", strings.Join(options, ", "), b.Market)
+	}
+	if b.FundingRates && !b.futures() {
+		return errors.New("funding_rates requires a futures market")
+	}
+	if b.Basis && !b.futures() {
+		return errors.New("basis requires a futures market")
+	}
+	if b.OpenInterest && !b.futures() {
+		return errors.New("open_interest requires a futures market")
+	}
+	if b.OpenInterestPeriod != "" {
+		if b.Market != marketUSDMFutures {
+			return errors.New("open_interest_period requires the usdm_futures market")
+		}
+		if !slices.Contains(futuresDataPeriods, b.OpenInterestPeriod) {
+			return fmt.Errorf("invalid open_interest_period %q, supported are %v", b.OpenInterestPeriod, futuresDataPeriods)
+		}
+	}
+	if b.FuturesPositions {
+		if !b.futures() {
+			return errors.New("futures_positions requires a futures market")
+		}
+		if !b.canSign() {
+			return errors.New("futures positions require api_key and api_secret or private_key_file to be set")
+		}
+	}
+	if b.Liquidations && !b.futures() {
+		return errors.New("liquidations requires a futures market")
+	}
+	if b.SentimentPeriod != "" {
+		if b.Market != marketUSDMFutures {
+			return errors.New("sentiment_period requires the usdm_futures market")
+		}
+		if !slices.Contains(futuresDataPeriods, b.SentimentPeriod) {
+			return fmt.Errorf("invalid sentiment_period %q, supported are %v", b.SentimentPeriod, futuresDataPeriods)
+		}
+	}
+	if b.TopSymbolsByVolume < 0 {
+		return errors.New("top_symbols_by_volume must not be negative")
+	}
+	top := b.TopSymbolsByVolume > 0
+	if top && (b.AllSymbols || b.Symbol != "" || len(b.Symbols) > 0 || b.BaseAsset != "") {
+		return errors.New("top_symbols_by_volume cannot be combined with all_symbols, symbol, symbols or base_asset")
+	}
+	if b.SymbolsFile != "" && (b.AllSymbols || top || quoteSelection) {
+		return errors.New("symbols_file cannot be combined with all_symbols, top_symbols_by_volume or quote assets without base_asset")
+	}
+	selection := b.AllSymbols || top || quoteSelection
+	if !selection && b.SymbolsFile == "" && b.Symbol == "" && len(b.Symbols) == 0 && (b.BaseAsset == "" || !hasQuotes) {
+		return errors.New("symbol, symbols or base_asset and quote_asset or quote_assets must be set")
+	}
+	if quoteSelection {
+		b.quotes = make([]string, 0, len(b.QuoteAssets)+1)
+		if b.QuoteAsset != "" {
+			b.quotes = append(b.quotes, strings.ToUpper(b.QuoteAsset))
+		}
+		for _, quote := range b.QuoteAssets {
+			b.quotes = append(b.quotes, strings.ToUpper(quote))
+		}
+	}
+	if (len(b.SymbolInclude) > 0 || len(b.SymbolExclude) > 0) && !selection {
+		return errors.New("symbol_include and symbol_exclude require all_symbols, top_symbols_by_volume or quote assets without base_asset")
+	}
+	b.dynamic = selection || b.SymbolsFile != ""
+	var err error
+	if b.symbolInclude, err = compileSymbolPatterns("symbol_include", b.SymbolInclude); err != nil {
+		return err
+	}
+	if b.symbolExclude, err = compileSymbolPatterns("symbol_exclude", b.SymbolExclude); err != nil {
+		return err
+	}
+	b.Symbol = strings.ToUpper(b.Symbol)
+	for i, symbol := range b.Symbols {
+		b.Symbols[i] = strings.ToUpper(symbol)
+	}
+	b.ConvertTo = strings.ToUpper(b.ConvertTo)
+	if b.RateLimitWeight <= 0 {
+		return errors.New("rate_limit_weight must be positive")
+	}
+	if b.exportStart, err = parseExportTime("export_start", b.ExportStart); err != nil {
+		return err
+	}
+	if b.exportEnd, err = parseExportTime("export_end", b.ExportEnd); err != nil {
+		return err
+	}
+	if b.BackfillDuration < 0 {
+		return errors.New("backfill_duration must not be negative")
+	}
+	if b.BackfillDuration > 0 && !b.exportStart.IsZero() {
+		return errors.New("backfill_duration cannot be combined with export_start")
+	}
+	if b.exportStart.IsZero() {
+		if !b.exportEnd.IsZero() {
+			return errors.New("export_end requires export_start to be set")
+		}
+		b.exported = b.BackfillDuration == 0
+	} else if !b.exportEnd.IsZero() && b.exportEnd.Before(b.exportStart) {
+		return errors.New("export_end must not be before export_start")
+	}
+	if b.ExportInterval == "" {
+		b.ExportInterval = "1m"
+	}
+	if _, found := klineIntervals[b.ExportInterval]; !found {
+		return fmt.Errorf("invalid export_interval %q", b.ExportInterval)
+	}
+	if b.KlineInterval != "" {
+		if _, found := klineIntervals[b.KlineInterval]; !found {
+			return fmt.Errorf("invalid kline_interval %q", b.KlineInterval)
+		}
+		// Continue the backfilled klines with the collected ones
+		if b.BackfillDuration > 0 {
+			b.ExportInterval = b.KlineInterval
+		}
+	}
+	switch b.KlineTimestamp {
+	case "":
+		b.KlineTimestamp = "open"
+	case "open", "close":
+	default:
+		return fmt.Errorf("invalid kline_timestamp %q", b.KlineTimestamp)
+	}
+	b.futuresDataSeen = make(map[string]int64)
+	b.fundingDue = make(map[string]int64)
+	if b.GapFill && b.GapFillThreshold < config.Duration(time.Minute) {
+		return errors.New("gap_fill_threshold must be at least one minute")
 	}
-	b.Log.AddAttribute("symbol", b.BaseAsset+b.QuoteAsset)
 
-	b.tags = map[string]string{
-		"base":  b.BaseAsset,
-		"quote": b.QuoteAsset,
+	for _, m := range b.P2P {
+		if err := m.init(); err != nil {
+			return err
+		}
+	}
+	for _, q := range b.ConvertQuotes {
+		if err := q.init(); err != nil {
+			return err
+		}
+	}
+	if len(b.ConvertQuotes) > 0 && !b.canSign() {
+		return errors.New("convert quotes require api_key and api_secret or private_key_file to be set")
 	}
 
-	var (
-		query string = fmt.Sprintf("symbol=%s%s", b.BaseAsset, b.QuoteAsset)
-		err   error
-		r     *http.Request
-	)
+	for _, category := range b.Announcements {
+		if _, found := announcementCatalogs[category]; !found {
+			return fmt.Errorf("invalid announcement category %q", category)
+		}
+	}
 
-	b.Log.Trace("Creating URLs")
-	b.priceURL, err = url.Parse(priceUrlString + "?" + query)
-	if err != nil {
-		return fmt.Errorf("failed to parse url %s: %w", priceUrlString, err)
+	switch b.SignatureMethod {
+	case "":
+		b.SignatureMethod = signatureHMAC
+	case signatureHMAC, signatureEd25519, signatureRSA:
+	default:
+		return fmt.Errorf("invalid signature_method %q", b.SignatureMethod)
+	}
+	if b.SignatureMethod != signatureHMAC {
+		if b.PrivateKeyFile == "" {
+			return fmt.Errorf("signature method %q requires private_key_file to be set", b.SignatureMethod)
+		}
+		if b.privateKey, err = loadPrivateKey(b.PrivateKeyFile, b.SignatureMethod); err != nil {
+			return err
+		}
 	}
 
-	b.exchangeInfoURL, err = url.Parse(exchangeInfoUrlString + "?" + query)
-	if err != nil {
-		return fmt.Errorf("failed to parse url %s: %w", exchangeInfoUrlString, err)
+	if b.RecvWindow == 0 {
+		b.RecvWindow = config.Duration(5 * time.Second)
+	}
+	if b.RecvWindow < 0 || b.RecvWindow > config.Duration(time.Minute) {
+		return errors.New("recv_window must be positive and at most one minute")
 	}
 
-	b.Log.Infof("Verifying requested symbol %s", b.BaseAsset+b.QuoteAsset)
+	b.indexCompositions = make(map[string]string, len(b.IndexInfo))
+	b.usedWeights = make(map[string]int64)
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
-	defer cancel()
-	if r, err = http.NewRequestWithContext(ctx, http.MethodGet, b.exchangeInfoURL.String(), nil); err != nil {
-		return fmt.Errorf("failed to create request for %s: %w", b.exchangeInfoURL.String(), err)
+	if len(b.LeverageBrackets) > 0 {
+		if !b.canSign() {
+			return errors.New("leverage brackets require api_key and api_secret or private_key_file to be set")
+		}
+		for i, symbol := range b.LeverageBrackets {
+			b.LeverageBrackets[i] = strings.ToUpper(symbol)
+		}
 	}
-	r.Header = header
+	b.leverageBracketsSeen = make(map[string]string, len(b.LeverageBrackets))
 
-	resp, err := b.client.Do(r)
+	if b.SymbolWarnings && !slices.Contains(b.Announcements, "delisting") {
+		b.Log.Warn("Delisting warnings require the \"delisting\" announcements to be collected")
+	}
+
+	if b.AccountBalances && !b.canSign() {
+		return errors.New("account balances require api_key and api_secret or private_key_file to be set")
+	}
+	if b.AccountInfo && !b.canSign() {
+		return errors.New("account information requires api_key and api_secret or private_key_file to be set")
+	}
+	if len(b.AccountSnapshots) > 0 {
+		if !b.canSign() {
+			return errors.New("account snapshots require api_key and api_secret or private_key_file to be set")
+		}
+		for i, kind := range b.AccountSnapshots {
+			b.AccountSnapshots[i] = strings.ToLower(kind)
+			if !slices.Contains(snapshotTypes, b.AccountSnapshots[i]) {
+				return fmt.Errorf("invalid account snapshot type %q, supported are %v", kind, snapshotTypes)
+			}
+		}
+	}
+	if b.OpenOrders && !b.canSign() {
+		return errors.New("open orders require api_key and api_secret or private_key_file to be set")
+	}
+	if b.MyTrades && !b.canSign() {
+		return errors.New("fills require api_key and api_secret or private_key_file to be set")
+	}
+	if b.TradeFees && !b.canSign() {
+		return errors.New("trade fees require api_key and api_secret or private_key_file to be set")
+	}
+	if b.CommissionRates && !b.canSign() {
+		return errors.New("commission rates require api_key and api_secret or private_key_file to be set")
+	}
+	if (b.DepositHistory || b.WithdrawalHistory) && !b.canSign() {
+		return errors.New("transfer history requires api_key and api_secret or private_key_file to be set")
+	}
+	if (b.MarginAccount || b.IsolatedAccount) && !b.canSign() {
+		return errors.New("margin account requires api_key and api_secret or private_key_file to be set")
+	}
+	if b.CryptoLoans && !b.canSign() {
+		return errors.New("crypto loans require api_key and api_secret or private_key_file to be set")
+	}
+	if b.SimpleEarn && !b.canSign() {
+		return errors.New("positions of Simple Earn require api_key and api_secret or private_key_file to be set")
+	}
+	if len(b.Staking) > 0 {
+		if !b.canSign() {
+			return errors.New("staking requires api_key and api_secret or private_key_file to be set")
+		}
+		for i, asset := range b.Staking {
+			b.Staking[i] = strings.ToUpper(asset)
+			if !slices.Contains(stakingAssets, b.Staking[i]) {
+				return fmt.Errorf("invalid staking asset %q, supported are %v", asset, stakingAssets)
+			}
+		}
+	}
+	if b.SubAccounts && !b.canSign() {
+		return errors.New("sub-accounts require api_key and api_secret or private_key_file to be set")
+	}
+	if b.KeyPermissions && !b.canSign() {
+		return errors.New("API key permissions require api_key and api_secret or private_key_file to be set")
+	}
+	for _, name := range b.ExpectedPermissions {
+		if !slices.Contains(permissionNames, name) {
+			return fmt.Errorf("invalid expected permission %q, supported are %v", name, permissionNames)
+		}
+	}
+	if b.PortfolioValuation != "" && !b.AccountBalances {
+		return errors.New("portfolio_valuation requires account_balances to be enabled")
+	}
+	b.PortfolioValuation = strings.ToUpper(b.PortfolioValuation)
+
+	if len(b.MarginAssets) > 0 || b.MarginIsolated {
+		if !b.canSign() {
+			return errors.New("margin interest rates require api_key and api_secret or private_key_file to be set")
+		}
+		for i, asset := range b.MarginAssets {
+			b.MarginAssets[i] = strings.ToUpper(asset)
+		}
+	}
+
+	if b.RecentTrades < 0 || b.RecentTrades > historicalTradesLimit {
+		return fmt.Errorf("recent_trades %d not between 0 and %d", b.RecentTrades, historicalTradesLimit)
+	}
+
+	for _, window := range b.RollingWindows {
+		if !rollingWindowSize.MatchString(window) {
+			return fmt.Errorf("invalid rolling window %q", window)
+		}
+	}
+
+	// Binance does not accept a leading plus sign for the offset
+	b.TradingDayTimezone = strings.TrimPrefix(b.TradingDayTimezone, "+")
+	if b.TradingDayTimezone == "" {
+		b.TradingDayTimezone = "0"
+	}
+	if !timezoneOffset.MatchString(b.TradingDayTimezone) {
+		return fmt.Errorf("invalid trading_day_timezone %q", b.TradingDayTimezone)
+	}
+
+	if b.Depth {
+		if b.DepthLimit < 1 || b.DepthLimit > 5000 {
+			return fmt.Errorf("depth_limit %d not between 1 and 5000", b.DepthLimit)
+		}
+		switch b.DepthLayout {
+		case "":
+			b.DepthLayout = depthLayoutAggregate
+		case depthLayoutAggregate, depthLayoutPerLevel:
+		default:
+			return fmt.Errorf("invalid depth_layout %q", b.DepthLayout)
+		}
+		for _, bps := range b.DepthBps {
+			if bps <= 0 {
+				return fmt.Errorf("depth_bps %d must be positive", bps)
+			}
+		}
+		if b.DepthPressureLevels < 0 {
+			return errors.New("depth_pressure_levels must not be negative")
+		}
+		for _, size := range b.SlippageOrderSizes {
+			if size <= 0 {
+				return fmt.Errorf("slippage order size %v must be positive", size)
+			}
+		}
+	}
+
+	b.state = state{
+		LastPrice:        make(map[string]time.Time),
+		LastAnnouncement: make(map[string]int64),
+		LastTradeID:      make(map[string]int64),
+		LastAggTradeID:   make(map[string]int64),
+		LastMyTradeID:    make(map[string]int64),
+		LastTransfer:     make(map[string]int64),
+		PendingTransfer:  make(map[string]pendingTransfer),
+		LastSnapshot:     make(map[string]int64),
+		LastFunding:      make(map[string]int64),
+		LastKline:        make(map[string]time.Time),
+	}
+
+	if b.apiURL == "" {
+		b.apiURL = baseApiUrlString
+	}
+	if b.p2pURL == "" {
+		b.p2pURL = p2pURLString
+	}
+	if b.announcementsURL == "" {
+		b.announcementsURL = announcementsURLString
+	}
+	if b.futuresURL == "" {
+		b.futuresURL = futuresURLString
+	}
+	if b.coinmURL == "" {
+		b.coinmURL = coinmFuturesURLString
+	}
+	if b.futuresDataURL == "" {
+		b.futuresDataURL = futuresDataURLString
+	}
+	if b.futuresV2URL == "" {
+		b.futuresV2URL = futuresV2URLString
+	}
+	if b.streamURL == "" {
+		b.streamURL = usdmStreamURLString
+		if b.Market == marketCOINMFutures {
+			b.streamURL = coinmStreamURLString
+		}
+	}
+	// The futures APIs provide the market data under the same endpoints
+	b.spotURL = b.apiURL
+	switch b.Market {
+	case marketUSDMFutures:
+		b.apiURL = b.futuresURL
+	case marketCOINMFutures:
+		b.apiURL = b.coinmURL
+	}
+	if b.sapiURL == "" {
+		b.sapiURL = sapiURLString
+	}
+	if b.sapiV2URL == "" {
+		b.sapiV2URL = sapiV2URLString
+	}
+	if b.sapiV4URL == "" {
+		b.sapiV4URL = sapiV4URLString
+	}
+	if b.probeURLs == nil {
+		b.probeURLs = make(map[string]string, len(probeHosts))
+		for _, host := range probeHosts {
+			b.probeURLs[host] = "https://" + host + "/api/v3"
+		}
+	}
+	if b.IPVersion == "" {
+		b.IPVersion = "any"
+	}
+	if _, found := ipVersions[b.IPVersion]; !found {
+		return fmt.Errorf("invalid ip_version %q", b.IPVersion)
+	}
+	if b.DNSCacheTTL > 0 {
+		b.dnsCache = newDNSCache(time.Duration(b.DNSCacheTTL))
+	}
+	transport, err := b.newTransport()
 	if err != nil {
-		return fmt.Errorf("failed to get response from %s: %w", b.exchangeInfoURL.String(), err)
-	} else if resp.StatusCode != http.StatusOK {
-		p := new(payload)
-		if err := json.NewDecoder(resp.Body).Decode(p); err != nil {
-			return fmt.Errorf("cannot decode response from %s: %w", b.exchangeInfoURL.String(), err)
+		return err
+	}
+	b.client = &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(b.Timeout),
+	}
+	if err := b.initTracing(); err != nil {
+		return err
+	}
+	if b.AuditLog != "" {
+		if b.AuditKey.Empty() {
+			return errors.New("audit_log requires audit_key to be set")
+		}
+		if err := b.openAuditLog(); err != nil {
+			return err
 		}
-		return fmt.Errorf("binance responsed with status %s (code %d) for symbol %s", p.Msg, p.Code, b.BaseAsset+b.QuoteAsset)
+	}
+
+	if b.RateLimitGroup != "" {
+		b.Log.Debugf("Using shared rate-limit group %q", b.RateLimitGroup)
+		b.budget, err = sharedBudget(b.RateLimitGroup, b.RateLimitWeight)
+		if err == nil && b.budget.limit != b.RateLimitWeight {
+			b.Log.Warnf("Rate-limit group %q already uses a weight of %d, ignoring setting of %d",
+				b.RateLimitGroup, b.budget.limit, b.RateLimitWeight)
+		}
+	} else {
+		b.budget, err = newWeightBudget(b.RateLimitWeight)
+	}
+	if err != nil {
+		return fmt.Errorf("creating rate-limit budget failed: %w", err)
+	}
+
+	if err := b.resolvePairs(); err != nil {
+		return err
+	}
+	if b.ConvertTo != "" {
+		if err := b.resolveConversions(); err != nil {
+			return err
+		}
+	}
+	if len(b.pairs) == 1 {
+		b.Log.AddAttribute("symbol", b.pairs[0].symbol)
 	}
 	b.Log.Info("plugin initialized successfully")
 	return nil
 }
 
+// Stop closes the liquidation stream and releases the resources of the
+// plugin, i.e. exports the pending trace spans, closes the audit log and the
+// idle connections to Binance.
+func (b *Binance) Stop() {
+	b.stopLiquidations()
+	b.stopTracing()
+	b.closeAuditLog()
+	if b.client != nil {
+		b.client.CloseIdleConnections()
+	}
+}
+
 func (b *Binance) Gather(acc telegraf.Accumulator) error {
+	endSpan := b.startGatherSpan()
+	defer endSpan()
 
-	var (
-		err    error
-		r      *http.Request
-		resp   *http.Response
-		cancel context.CancelFunc
-	)
+	defer b.addAPIErrors(acc)
+	if b.HTTPTiming {
+		defer b.addHTTPTimings(acc)
+	}
+	if b.RateLimitUsage {
+		defer b.addUsedWeights(acc)
+	}
+	defer b.addBackOff(acc)
 
-	if r, cancel, err = b.createRequest(); err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	// Do not make things worse while Binance asks to back off
+	if until, statusCode := b.budget.blocked(time.Now()); !until.IsZero() {
+		b.Log.Debugf("Skipping gather cycle due to status %d until %s", statusCode, until.Format(time.RFC3339))
+		return nil
+	}
+
+	if b.dynamic {
+		acc.AddError(b.refreshPairs())
+	}
+
+	if !b.exported {
+		b.export(acc)
+		b.exported = true
+	}
+
+	if b.SystemStatus {
+		acc.AddError(b.gatherSystemStatus(acc))
+	}
+	b.gatherP2P(acc)
+	if b.SymbolWarnings || b.StatusTag || b.SymbolFilters {
+		acc.AddError(b.gatherSymbolStatus(acc))
+	}
+	if b.ExchangeInfoSummary {
+		acc.AddError(b.gatherExchangeInfoSummary(acc))
+	}
+	b.gatherAnnouncements(acc)
+	b.gatherIndexInfo(acc)
+	if b.ProbeEndpoints {
+		b.gatherProbes(acc)
+	}
+	b.gatherMarginInterest(acc)
+	if b.MarginAccount {
+		acc.AddError(b.gatherMarginAccount(acc))
+	}
+	if b.IsolatedAccount {
+		acc.AddError(b.gatherIsolatedMarginAccount(acc))
+	}
+	if b.CryptoLoans {
+		acc.AddError(b.gatherCryptoLoans(acc))
+	}
+	if b.SimpleEarn {
+		b.gatherSimpleEarn(acc)
+	}
+	b.gatherStaking(acc)
+	if b.SubAccounts {
+		acc.AddError(b.gatherSubAccounts(acc))
+	}
+	if b.KeyPermissions {
+		acc.AddError(b.gatherKeyPermissions(acc))
+	}
+	if len(b.ConvertQuotes) > 0 {
+		acc.AddError(b.gatherConvertQuotes(acc))
 	}
+	if b.AccountBalances {
+		acc.AddError(b.gatherBalances(acc))
+	}
+	if b.AccountInfo {
+		acc.AddError(b.gatherAccountInfo(acc))
+	}
+	if len(b.AccountSnapshots) > 0 {
+		b.gatherAccountSnapshots(acc)
+	}
+	if b.OpenOrders {
+		acc.AddError(b.gatherOpenOrders(acc))
+	}
+	if b.MyTrades {
+		b.gatherMyTrades(acc)
+	}
+	if b.TradeFees {
+		acc.AddError(b.gatherTradeFees(acc))
+	}
+	if b.CommissionRates {
+		b.gatherCommissionRates(acc)
+	}
+	if b.DepositHistory {
+		acc.AddError(b.gatherDeposits(acc))
+	}
+	if b.WithdrawalHistory {
+		acc.AddError(b.gatherWithdrawals(acc))
+	}
+	if len(b.LeverageBrackets) > 0 {
+		acc.AddError(b.gatherLeverageBrackets(acc))
+	}
+	if b.OpenInterestPeriod != "" {
+		b.gatherOpenInterestHistory(acc)
+	}
+	if b.SentimentPeriod != "" {
+		b.gatherSentiment(acc)
+	}
+	if b.FuturesPositions {
+		acc.AddError(b.gatherFuturesPositions(acc))
+	}
+	if b.KlineInterval != "" {
+		b.gatherKlines(acc)
+	}
+	if b.Collect24hStats {
+		acc.AddError(b.gather24hStats(acc))
+	}
+	if len(b.RollingWindows) > 0 {
+		b.gatherRollingWindows(acc)
+	}
+	if b.TradingDay {
+		b.gatherTradingDay(acc)
+	}
+	if b.HistoricalTrades {
+		b.gatherHistoricalTrades(acc)
+	}
+	if b.AggTrades {
+		b.gatherAggTrades(acc)
+	}
+	if b.RecentTrades > 0 {
+		b.gatherRecentTrades(acc)
+	}
+	if b.Depth {
+		b.gatherDepth(acc)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
 	defer cancel()
 
-	if resp, err = b.client.Do(r); err != nil {
-		acc.AddError(fmt.Errorf("failed to get response from %s: %w", b.priceURL.String(), err))
+	ticks, err := b.prices(ctx)
+	if err != nil {
+		acc.AddError(err)
 		return nil
-	} else if resp.StatusCode != http.StatusOK {
-		p := new(payload)
-		if err = json.NewDecoder(resp.Body).Decode(p); err != nil {
-			return fmt.Errorf("cannot decode response from %s: %w", b.priceURL.String(), err)
+	}
+
+	var indices map[string]premiumIndex
+	if b.futures() {
+		if indices, err = b.premiumIndices(ctx); err != nil {
+			acc.AddError(fmt.Errorf("querying mark prices failed: %w", err))
+		}
+		if b.FundingRates {
+			b.gatherFundingHistory(acc, indices)
+			b.addFundingRates(acc, indices)
+		}
+		if b.OpenInterest {
+			b.gatherOpenInterest(acc, indices)
+		}
+	}
+	var spot map[string]tick
+	if b.Basis {
+		if spot, err = b.spotTicks(ctx); err != nil {
+			acc.AddError(fmt.Errorf("querying spot prices failed: %w", err))
 		}
-		acc.AddError(fmt.Errorf("binance responsed with status %s (code %d) for symbol %s", p.Msg, p.Code, b.BaseAsset+b.QuoteAsset))
 	}
 
-	fields := make(map[string]interface{})
-	t := new(tick)
-	if err = json.NewDecoder(resp.Body).Decode(t); err != nil {
-		acc.AddError(fmt.Errorf("cannot decode response from %s: %w", b.priceURL.String(), err))
-		return nil
+	now := time.Now()
+	for _, p := range b.pairs {
+		t, found := ticks[p.symbol]
+		if !found {
+			acc.AddError(fmt.Errorf("no price received for symbol %s", p.symbol))
+			continue
+		}
+		price, err := strconv.ParseFloat(strings.TrimSpace(t.Price), 64)
+		if err != nil {
+			acc.AddError(fmt.Errorf("cannot parse price %s of symbol %s: %w", t.Price, p.symbol, err))
+			continue
+		}
+
+		if b.GapFill {
+			acc.AddError(b.fillGap(acc, p, now))
+		}
+		// The average price is shared by the price fields and the price bands
+		fields := map[string]interface{}{"price": p.price(price)}
+		if index, found := indices[p.symbol]; found {
+			acc.AddError(addPremiumIndex(fields, p, index))
+			if spot != nil {
+				acc.AddError(addBasis(fields, p, spot))
+			}
+		}
+		withBands := b.PriceBands && p.bands != nil
+		if b.AvgPrice || withBands {
+			average, mins, err := b.averagePrice(ctx, p)
+			if err != nil {
+				acc.AddError(err)
+				withBands = false
+			} else if b.AvgPrice {
+				fields["avg_price"] = p.price(average)
+				fields["avg_price_mins"] = mins
+			}
+			if withBands {
+				addPriceBands(acc, p, price, average, mins)
+			}
+		}
+		tags := p.priceTags
+		if b.StatusTag && p.status != statusTrading {
+			tags = maps.Clone(tags)
+			tags["status"] = p.status
+		}
+		if b.ConvertTo != "" {
+			if converted, err := b.convert(p, p.price(price), ticks); err != nil {
+				acc.AddError(err)
+			} else if converted != 0 {
+				fields["price_converted"] = converted
+				tags = maps.Clone(tags)
+				tags["convert_quote"] = b.ConvertTo
+			}
+		}
+		acc.AddFields("binance", fields, tags)
+		b.state.LastPrice[p.symbol] = now
 	}
+	return nil
+}
 
-	fields["price"], err = strconv.ParseFloat(strings.TrimSpace(t.Price), 64)
+// prices queries the current prices of all pairs and of the cross pairs
+// for converting them in a single request
+func (b *Binance) prices(ctx context.Context) (map[string]tick, error) {
+	symbols := make([]string, 0, len(b.pairs)+len(b.conversions))
+	for _, p := range b.pairs {
+		symbols = append(symbols, p.symbol)
+	}
+	for _, c := range b.conversions {
+		if !slices.Contains(symbols, c.symbol) {
+			symbols = append(symbols, c.symbol)
+		}
+	}
+
+	var ticks []tick
+	if b.AllSymbols {
+		// Without a symbol filter the prices of all symbols are returned
+		if err := b.query(ctx, priceEndpoint, nil, multiPriceWeight, &ticks); err != nil {
+			return nil, err
+		}
+	} else if len(symbols) == 0 {
+		return nil, errors.New("no symbols to collect")
+	} else if len(symbols) == 1 && b.Market != marketCOINMFutures {
+		// The COIN-M futures API returns a list even for a single symbol
+		var t tick
+		if err := b.query(ctx, priceEndpoint, url.Values{"symbol": symbols}, priceWeight, &t); err != nil {
+			return nil, err
+		}
+		ticks = append(ticks, t)
+	} else if b.futures() {
+		// The futures APIs do not support selecting multiple symbols
+		if err := b.query(ctx, priceEndpoint, nil, multiPriceWeight, &ticks); err != nil {
+			return nil, err
+		}
+	} else {
+		buf, err := json.Marshal(symbols)
+		if err != nil {
+			return nil, err
+		}
+		params := url.Values{"symbols": {string(buf)}}
+		if err := b.query(ctx, priceEndpoint, params, multiPriceWeight, &ticks); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make(map[string]tick, len(ticks))
+	for _, t := range ticks {
+		result[t.Symbol] = t
+	}
+	return result, nil
+}
+
+// query requests the given endpoint after taking the weight of the request
+// from the rate-limit budget and decodes the response into v if not nil.
+func (b *Binance) query(ctx context.Context, endpoint string, params url.Values, weight int64, v interface{}) error {
+	return b.queryURL(ctx, b.apiURL+endpoint, params, weight, v)
+}
+
+// queryFutures is the equivalent of query for the USDⓈ-M futures API
+func (b *Binance) queryFutures(ctx context.Context, endpoint string, params url.Values, weight int64, v interface{}) error {
+	return b.queryURL(ctx, b.futuresURL+endpoint, params, weight, v)
+}
+
+func (b *Binance) queryURL(ctx context.Context, base string, params url.Values, weight int64, v interface{}) error {
+	address := withParams(base, params)
+	if err := b.budget.reserve(time.Now(), weight); err != nil {
+		return fmt.Errorf("skipping request to %s: %w", address, err)
+	}
+	return b.fetch(ctx, address, v)
+}
+
+func withParams(address string, params url.Values) string {
+	if len(params) > 0 {
+		address += "?" + params.Encode()
+	}
+	return address
+}
+
+// fetch requests the given address and decodes the response into v if not
+// nil. The caller is responsible for accounting the request weight.
+func (b *Binance) fetch(ctx context.Context, address string, v interface{}) error {
+	r, err := newRequest(ctx, http.MethodGet, address, nil)
 	if err != nil {
-		acc.AddError(fmt.Errorf("cannot parse price %s: %w", t.Price, err))
+		return err
 	}
+	return b.do(r, v)
+}
 
-	acc.AddFields("binance", fields, b.tags)
-	return nil
+func newRequest(ctx context.Context, method, address string, body io.Reader) (*http.Request, error) {
+	r, err := http.NewRequestWithContext(ctx, method, address, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", address, err)
+	}
+	r.Header = header.Clone()
+	return r, nil
 }
 
-func (b *Binance) createRequest() (*http.Request, context.CancelFunc, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
-	r, _ := http.NewRequestWithContext(ctx, http.MethodGet, b.priceURL.String(), nil)
-	r.Header = header
-	return r, cancel, nil
+// do sends the request and decodes the response into v if not nil
+func (b *Binance) do(r *http.Request, v interface{}) error {
+	_, err := b.send(r, v)
+	return err
+}
+
+// send is the equivalent of do additionally returning the status code of the
+// response or zero if no response was received.
+func (b *Binance) send(r *http.Request, v interface{}) (statusCode int, err error) {
+	// Keep the signature out of error messages
+	address := redactURL(r.URL)
+	span := b.startRequestSpan(r)
+	defer func() {
+		endRequestSpan(span, statusCode, err)
+	}()
+
+	var timing *requestTiming
+	if b.HTTPTiming {
+		r, timing = traceRequest(r)
+		defer func() {
+			timing.done = time.Now()
+			b.timings = append(b.timings, timing)
+		}()
+	}
+
+	if b.DebugHTTP {
+		b.dumpRequest(r)
+	}
+	resp, err := b.client.Do(r)
+	if err != nil {
+		return statusCode, fmt.Errorf("failed to get response from %s: %w", address, err)
+	}
+	defer resp.Body.Close()
+	statusCode = resp.StatusCode
+	if b.DebugHTTP {
+		if err := b.dumpResponse(r, resp); err != nil {
+			return statusCode, fmt.Errorf("failed to read response from %s: %w", address, err)
+		}
+	}
+	b.recordUsedWeight(r.URL.Host, resp.Header)
+	if timing != nil {
+		timing.statusCode = statusCode
+	}
+
+	if isBackOffStatus(resp.StatusCode) {
+		b.backOff(resp)
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := new(apiError)
+		if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil {
+			return statusCode, fmt.Errorf("cannot decode response from %s: %w", address, err)
+		}
+		b.failedRequests = append(b.failedRequests, failedRequest{
+			endpoint:   r.URL.Path,
+			statusCode: resp.StatusCode,
+			err:        apiErr,
+			ts:         time.Now(),
+		})
+		b.logAPIError(r.URL.Path, apiErr)
+		return statusCode, fmt.Errorf("binance responded with status %w for %s", apiErr, address)
+	}
+
+	if v == nil {
+		return statusCode, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return statusCode, fmt.Errorf("cannot decode response from %s: %w", address, err)
+	}
+	return statusCode, nil
+}
+
+// compileSymbolPatterns compiles the regular expressions of the given setting
+func compileSymbolPatterns(setting string, patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %w", setting, pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// parseFloatFields parses the given values and adds them to the fields
+func parseFloatFields(fields map[string]interface{}, values map[string]string) error {
+	for name, value := range values {
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("cannot parse %s %q: %w", name, value, err)
+		}
+		fields[name] = v
+	}
+	return nil
 }
 
 func init() {
 	inputs.Add("binance", func() telegraf.Input {
 		return &Binance{
-			client: &http.Client{Timeout: 5 * time.Second},
 			// Set the default timeout here to distinguish it from the user setting it to zero
-			Timeout: config.Duration(5 * time.Second),
+			Timeout:              config.Duration(5 * time.Second),
+			RateLimitWeight:      6000,
+			GapFillThreshold:     config.Duration(2 * time.Minute),
+			GapFillMaxAge:        config.Duration(24 * time.Hour),
+			AnnouncementsRefresh: config.Duration(10 * time.Minute),
+			DepthLimit:           10,
+			ProbeInterval:        config.Duration(5 * time.Minute),
+			MarginVipLevel:       -1,
+			SymbolStatusRefresh:  config.Duration(10 * time.Minute),
+			ExchangeInfoRefresh:  config.Duration(time.Hour),
+			SymbolsRefresh:       config.Duration(time.Hour),
+			ServerTimeRefresh:    config.Duration(time.Hour),
+			PermissionsRefresh:   config.Duration(time.Hour),
 		}
 	})
 }
@@ -0,0 +1,5330 @@
+package binance
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/armon/go-socks5"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+// newTestServer serves the files in testdata named after the requested
+// endpoint and symbol or coin, e.g. "ticker_price_BTCEUR.json" for a request
+// to "/ticker/price?symbol=BTCEUR". Requests for multiple symbols are answered
+// with an array of the single-symbol files.
+func newTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(testdataHandler(t))
+}
+
+func testdataHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := strings.ReplaceAll(strings.TrimPrefix(r.URL.Path, "/"), "/", "_")
+
+		var buf []byte
+		var err error
+		if symbols := r.URL.Query().Get("symbols"); symbols != "" {
+			var list []string
+			if err := json.Unmarshal([]byte(symbols), &list); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				t.Error(err)
+				return
+			}
+			parts := make([]string, 0, len(list))
+			for _, symbol := range list {
+				var part []byte
+				if part, err = os.ReadFile(filepath.Join("testdata", name+"_"+symbol+".json")); err != nil {
+					break
+				}
+				parts = append(parts, string(part))
+			}
+			buf = []byte("[" + strings.Join(parts, ",") + "]")
+		} else {
+			for _, key := range []string{"symbol", "coin"} {
+				if value := r.URL.Query().Get(key); value != "" {
+					name += "_" + value
+				}
+			}
+			buf, err = os.ReadFile(filepath.Join("testdata", name+".json"))
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":-1121,"msg":"Invalid symbol."}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(buf); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func newTestPlugin(url string) *Binance {
+	return &Binance{
+		BaseAsset:       "BTC",
+		QuoteAsset:      "EUR",
+		Timeout:         config.Duration(5 * time.Second),
+		RateLimitWeight: 6000,
+		Log:             testutil.Logger{},
+		apiURL:          url,
+	}
+}
+
+func TestInitMissingAssets(t *testing.T) {
+	plugin := &Binance{
+		RateLimitWeight: 6000,
+		Log:             testutil.Logger{},
+	}
+	require.ErrorContains(t, plugin.Init(), "symbol, symbols or base_asset and quote_asset or quote_assets must be set")
+
+	plugin.QuoteAsset = "EUR"
+	plugin.Symbol = "BTCEUR"
+	require.ErrorContains(t, plugin.Init(), "quote_asset and quote_assets require base_asset to be set if combined with symbol or symbols")
+}
+
+func TestInitInvalidSymbol(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.QuoteAsset = "FOO"
+	require.ErrorContains(t, plugin.Init(), "none of the requested symbols is listed")
+}
+
+func TestGather(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{"price": 76543.21},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestGatherUSDMFutures(t *testing.T) {
+	tests := []struct {
+		name     string
+		symbols  []string
+		expected []telegraf.Metric
+	}{
+		{
+			name:    "single symbol",
+			symbols: []string{"BTCUSDT"},
+			expected: []telegraf.Metric{
+				metric.New(
+					"binance",
+					map[string]string{"base": "BTC", "quote": "USDT", "market": "usdm_futures"},
+					map[string]interface{}{"price": 82345.67, "mark_price": 82391.2, "index_price": 82362.45217391},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name:    "multiple symbols",
+			symbols: []string{"BTCUSDT", "EURUSDT"},
+			expected: []telegraf.Metric{
+				metric.New(
+					"binance",
+					map[string]string{"base": "BTC", "quote": "USDT", "market": "usdm_futures"},
+					map[string]interface{}{"price": 82345.67, "mark_price": 82391.2, "index_price": 82362.45217391},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"binance",
+					map[string]string{"base": "EUR", "quote": "USDT", "market": "usdm_futures"},
+					map[string]interface{}{"price": 1.085, "mark_price": 1.0853, "index_price": 1.08512043},
+					time.Unix(0, 0),
+				),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			defer server.Close()
+
+			// All market data is queried from the futures API
+			plugin := newTestPlugin("")
+			plugin.BaseAsset = ""
+			plugin.QuoteAsset = ""
+			plugin.Symbols = tt.symbols
+			plugin.Market = "usdm_futures"
+			plugin.futuresURL = server.URL
+			require.NoError(t, plugin.Init())
+			require.Equal(t, server.URL, plugin.apiURL)
+
+			var acc testutil.Accumulator
+			require.NoError(t, plugin.Gather(&acc))
+			require.Empty(t, acc.Errors)
+			testutil.RequireMetricsEqual(t, tt.expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime(), testutil.SortMetrics())
+		})
+	}
+}
+
+func TestGatherCOINMFutures(t *testing.T) {
+	// Serve the files prefixed with "dapi" to tell them from the spot data
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The exchange information always contains all symbols
+		if r.URL.Path == "/exchangeInfo" {
+			r.URL.RawQuery = ""
+		}
+		r.URL.Path = "/dapi" + r.URL.Path
+		testdataHandler(t)(w, r)
+	}))
+	defer server.Close()
+
+	plugin := newTestPlugin("")
+	plugin.BaseAsset = ""
+	plugin.QuoteAsset = ""
+	plugin.Symbols = []string{"BTCUSD_PERP", "BTCUSD_250627"}
+	plugin.Market = "coinm_futures"
+	plugin.StatusTag = true
+	plugin.coinmURL = server.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "USD", "market": "coinm_futures", "contract_type": "PERPETUAL"},
+			map[string]interface{}{"price": 82312.4, "mark_price": 82334.1, "index_price": 82362.45217391},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "USD", "market": "coinm_futures", "contract_type": "CURRENT_QUARTER"},
+			map[string]interface{}{"price": 83587.9, "mark_price": 83601.5, "index_price": 82362.45217391},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime(), testutil.SortMetrics())
+}
+
+func TestFundingRates(t *testing.T) {
+	var starts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fundingRate" {
+			starts = append(starts, r.URL.Query().Get("startTime"))
+		}
+		testdataHandler(t)(w, r)
+	}))
+	defer server.Close()
+
+	plugin := newTestPlugin("")
+	plugin.QuoteAsset = "USDT"
+	plugin.Market = "usdm_futures"
+	plugin.FundingRates = true
+	plugin.futuresURL = server.URL
+	require.NoError(t, plugin.Init())
+	// A restarted plugin catches up with the fundings settled meanwhile
+	plugin.state.LastFunding["BTCUSDT"] = 1741651200000
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_funding",
+			map[string]string{"base": "BTC", "quote": "USDT", "market": "usdm_futures", "type": "settled"},
+			map[string]interface{}{"funding_rate": 0.00003894, "mark_price": 79312.47391304},
+			time.UnixMilli(1741680000000),
+		),
+		metric.New(
+			"binance_funding",
+			map[string]string{"base": "BTC", "quote": "USDT", "market": "usdm_futures", "type": "settled"},
+			map[string]interface{}{"funding_rate": 0.0000712, "mark_price": 81873.9},
+			time.UnixMilli(1741708800000),
+		),
+		metric.New(
+			"binance_funding",
+			map[string]string{"base": "BTC", "quote": "USDT", "market": "usdm_futures", "type": "current"},
+			map[string]interface{}{"funding_rate": 0.0001, "interest_rate": 0.0001, "next_funding_time": int64(1741737600000)},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_funding" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+
+	// The funding at the next funding time is due but not yet published, so
+	// the next query continues after the last settled funding rate
+	require.NoError(t, plugin.Gather(&acc))
+	require.Equal(t, []string{"1741651200001", "1741708800001"}, starts)
+}
+
+func TestFundingRatesDue(t *testing.T) {
+	next := time.Now().Add(time.Hour).UnixMilli()
+	var starts []string
+	var settled []int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/premiumIndex", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"symbol":"BTCUSDT","markPrice":"82391.2","indexPrice":"82362.45","lastFundingRate":"0.0001","interestRate":"0.0001","nextFundingTime":%d}`, next)
+	})
+	mux.HandleFunc("/fundingRate", func(w http.ResponseWriter, r *http.Request) {
+		starts = append(starts, r.URL.Query().Get("startTime"))
+		rates := make([]string, 0, len(settled))
+		for _, ts := range settled {
+			rates = append(rates, fmt.Sprintf(`{"symbol":"BTCUSDT","fundingTime":%d,"fundingRate":"0.0001","markPrice":"82000.0"}`, ts))
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(rates, ",") + "]"))
+	})
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin("")
+	plugin.QuoteAsset = "USDT"
+	plugin.Market = "usdm_futures"
+	plugin.FundingRates = true
+	plugin.futuresURL = server.URL
+	require.NoError(t, plugin.Init())
+
+	// The first run starts with the fundings settled from now on instead of
+	// replaying the history, and does not query before the funding is due
+	var acc testutil.Accumulator
+	for range 2 {
+		require.NoError(t, plugin.Gather(&acc))
+		require.Empty(t, acc.Errors)
+	}
+	require.Empty(t, starts)
+	start := plugin.state.LastFunding["BTCUSDT"]
+	require.InDelta(t, time.Now().UnixMilli(), start, float64(time.Minute.Milliseconds()))
+
+	// Pretend the funding time passed since the start, the rate is queried
+	// until published
+	due := time.Now().Add(-time.Second).UnixMilli()
+	start = due - time.Hour.Milliseconds()
+	plugin.state.LastFunding["BTCUSDT"] = start
+	plugin.fundingDue["BTCUSDT"] = due
+	require.NoError(t, plugin.Gather(&acc))
+	settled = append(settled, due)
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, []string{strconv.FormatInt(start+1, 10), strconv.FormatInt(start+1, 10)}, starts)
+
+	var actual []int64
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_funding" && m.Tags()["type"] == "settled" {
+			actual = append(actual, m.Time().UnixMilli())
+		}
+	}
+	require.Equal(t, []int64{due}, actual)
+
+	// Afterwards the history is not queried before the next funding time
+	require.Equal(t, next, plugin.fundingDue["BTCUSDT"])
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, starts, 2)
+}
+
+func TestInitFundingRatesSpot(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.FundingRates = true
+	require.ErrorContains(t, plugin.Init(), "funding_rates requires a futures market")
+}
+
+func TestBasis(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	// The spot prices are queried from the spot API
+	plugin := newTestPlugin(server.URL)
+	plugin.QuoteAsset = "USDT"
+	plugin.Market = "usdm_futures"
+	plugin.Basis = true
+	plugin.futuresURL = server.URL
+	require.NoError(t, plugin.Init())
+	require.Equal(t, server.URL, plugin.spotURL)
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	mark, spot := 82391.2, 82345.67
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "USDT", "market": "usdm_futures"},
+			map[string]interface{}{
+				"price":         82345.67,
+				"mark_price":    82391.2,
+				"index_price":   82362.45217391,
+				"spot_price":    82345.67,
+				"basis":         mark - spot,
+				"basis_percent": (mark - spot) / spot * 100,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestSpotSymbol(t *testing.T) {
+	usdm := newPair(symbolInfo{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT"})
+	require.Equal(t, "BTCUSDT", usdm.spotSymbol())
+	coinm := newPair(symbolInfo{Symbol: "BTCUSD_PERP", BaseAsset: "BTC", QuoteAsset: "USD"})
+	require.Equal(t, "BTCUSDT", coinm.spotSymbol())
+}
+
+func TestOpenInterest(t *testing.T) {
+	var params []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/openInterestHist" {
+			params = append(params, r.URL.Query())
+		}
+		testdataHandler(t)(w, r)
+	}))
+	defer server.Close()
+
+	plugin := newTestPlugin("")
+	plugin.QuoteAsset = "USDT"
+	plugin.Market = "usdm_futures"
+	plugin.OpenInterest = true
+	plugin.OpenInterestPeriod = "5m"
+	plugin.futuresURL = server.URL
+	plugin.futuresDataURL = server.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	oi, mark := 81234.567, 82391.2
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_open_interest",
+			map[string]string{"base": "BTC", "quote": "USDT", "market": "usdm_futures", "period": "5m"},
+			map[string]interface{}{"open_interest": 81102.341, "open_interest_value": 6676823845.3421},
+			time.UnixMilli(1741734900000),
+		),
+		metric.New(
+			"binance_open_interest",
+			map[string]string{"base": "BTC", "quote": "USDT", "market": "usdm_futures"},
+			map[string]interface{}{"open_interest": oi, "open_interest_value": oi * mark},
+			time.UnixMilli(1741735124000),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_open_interest" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.SortMetrics())
+
+	// Only the most recent period is queried first, later ones continue
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, params, 2)
+	require.Equal(t, "1", params[0].Get("limit"))
+	require.Empty(t, params[0].Get("startTime"))
+	require.Equal(t, "1741734900001", params[1].Get("startTime"))
+}
+
+func TestInitInvalidOpenInterestPeriod(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.QuoteAsset = "USDT"
+	plugin.Market = "usdm_futures"
+	plugin.OpenInterestPeriod = "1m"
+	require.ErrorContains(t, plugin.Init(), `invalid open_interest_period "1m"`)
+
+	plugin = newTestPlugin("")
+	plugin.Market = "coinm_futures"
+	plugin.OpenInterestPeriod = "5m"
+	require.ErrorContains(t, plugin.Init(), "open_interest_period requires the usdm_futures market")
+}
+
+func TestSentiment(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin("")
+	plugin.QuoteAsset = "USDT"
+	plugin.Market = "usdm_futures"
+	plugin.SentimentPeriod = "5m"
+	plugin.futuresURL = server.URL
+	plugin.futuresDataURL = server.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	ts := time.UnixMilli(1741734900000)
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_long_short_ratio",
+			map[string]string{"base": "BTC", "quote": "USDT", "market": "usdm_futures", "period": "5m", "type": "global_accounts"},
+			map[string]interface{}{"long_short_ratio": 1.8105, "long_share": 0.6442, "short_share": 0.3558},
+			ts,
+		),
+		metric.New(
+			"binance_long_short_ratio",
+			map[string]string{"base": "BTC", "quote": "USDT", "market": "usdm_futures", "period": "5m", "type": "top_accounts"},
+			map[string]interface{}{"long_short_ratio": 1.4342, "long_share": 0.5892, "short_share": 0.4108},
+			ts,
+		),
+		metric.New(
+			"binance_long_short_ratio",
+			map[string]string{"base": "BTC", "quote": "USDT", "market": "usdm_futures", "period": "5m", "type": "top_positions"},
+			map[string]interface{}{"long_short_ratio": 1.1876, "long_share": 0.5429, "short_share": 0.4571},
+			ts,
+		),
+		metric.New(
+			"binance_taker_volume",
+			map[string]string{"base": "BTC", "quote": "USDT", "market": "usdm_futures", "period": "5m"},
+			map[string]interface{}{"buy_sell_ratio": 0.8734, "buy_volume": 412.732, "sell_volume": 472.554},
+			ts,
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_long_short_ratio" || m.Name() == "binance_taker_volume" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.SortMetrics())
+}
+
+func TestFuturesPositions(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	// Serve the files prefixed with the API version to tell them from spot
+	fapi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.URL.Path = "/fapi/v2" + r.URL.Path
+		signedHandler(t, "key", "secret")(w, r)
+	}))
+	defer fapi.Close()
+
+	plugin := newTestPlugin("")
+	plugin.QuoteAsset = "USDT"
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.Market = "usdm_futures"
+	plugin.FuturesPositions = true
+	plugin.futuresURL = server.URL
+	plugin.futuresV2URL = fapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	// Isolated positions have their own margin, cross positions share the
+	// margin balance of the account
+	wallet, pnl := 1095.604152, 508.22
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_futures_position",
+			map[string]string{"symbol": "BTCUSDT", "position_side": "BOTH", "margin_type": "isolated", "market": "usdm_futures"},
+			map[string]interface{}{
+				"position_amount":   0.2,
+				"entry_price":       79850.1,
+				"mark_price":        82391.2,
+				"unrealized_pnl":    508.22,
+				"liquidation_price": 72268.5316145,
+				"leverage":          10.0,
+				"isolated_wallet":   1095.604152,
+				"notional":          16478.24,
+				"maint_margin":      65.91296,
+				"margin_ratio":      65.91296 / (wallet + pnl),
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_futures_position",
+			map[string]string{"symbol": "ETHUSDT", "position_side": "BOTH", "margin_type": "cross", "market": "usdm_futures"},
+			map[string]interface{}{
+				"position_amount":   -3.0,
+				"entry_price":       1935.42,
+				"mark_price":        1922.04,
+				"unrealized_pnl":    40.14,
+				"liquidation_price": 2519.76531915,
+				"leverage":          20.0,
+				"isolated_wallet":   0.0,
+				"notional":          -5766.12,
+				"maint_margin":      57.3847,
+				"margin_ratio":      123.29766 / 5668.7921,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_futures_position" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), testutil.SortMetrics())
+}
+
+func TestInitFuturesPositions(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.Market = "usdm_futures"
+	plugin.FuturesPositions = true
+	require.ErrorContains(t, plugin.Init(), "futures positions require api_key")
+
+	plugin = newTestPlugin("")
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.FuturesPositions = true
+	require.ErrorContains(t, plugin.Init(), "futures_positions requires a futures market")
+}
+
+func TestLiquidations(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	upgrader := websocket.Upgrader{}
+	stream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stream" || r.URL.Query().Get("streams") != "btcusdt@forceOrder" {
+			w.WriteHeader(http.StatusNotFound)
+			t.Errorf("unexpected stream request %q", r.URL.String())
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		event := `{"stream":"btcusdt@forceOrder","data":{"e":"forceOrder","E":1741735124100,"o":{` +
+			`"s":"BTCUSDT","S":"SELL","o":"LIMIT","f":"IOC","q":"0.014","p":"82012.5","ap":"82103.1",` +
+			`"X":"FILLED","l":"0.014","z":"0.014","T":1741735124090}}}`
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(event)); err != nil {
+			t.Error(err)
+			return
+		}
+		// Keep the connection open until the plugin stops
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer stream.Close()
+
+	plugin := newTestPlugin("")
+	plugin.QuoteAsset = "USDT"
+	plugin.Market = "usdm_futures"
+	plugin.Liquidations = true
+	plugin.futuresURL = server.URL
+	plugin.streamURL = "ws" + strings.TrimPrefix(stream.URL, "http")
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	acc.Wait(1)
+	plugin.Stop()
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_liquidation",
+			map[string]string{"base": "BTC", "quote": "USDT", "market": "usdm_futures", "side": "sell"},
+			map[string]interface{}{
+				"price":           82012.5,
+				"avg_price":       82103.1,
+				"quantity":        0.014,
+				"filled_quantity": 0.014,
+				"status":          "FILLED",
+			},
+			time.UnixMilli(1741735124090),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestInitInvalidMarket(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.Market = "options"
+	require.ErrorContains(t, plugin.Init(), `invalid market "options"`)
+}
+
+func TestInitFuturesSpotOnlyOptions(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.Market = "usdm_futures"
+	plugin.TradingDay = true
+	plugin.AvgPrice = true
+	require.ErrorContains(t, plugin.Init(), "avg_price, trading_day not supported on the usdm_futures market")
+}
+
+func TestRateLimitBudget(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	// The budget only allows the exchange-info request during Init and a
+	// single price request
+	plugin := newTestPlugin(server.URL)
+	plugin.RateLimitWeight = exchangeInfoWeight + priceWeight
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Len(t, acc.GetTelegrafMetrics(), 1)
+
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, acc.Errors, 1)
+	require.ErrorIs(t, acc.Errors[0], errBudgetExhausted)
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
+
+func TestRateLimitGroup(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	// Both instances share the budget of the first one, allowing both
+	// initializations but only one price request in total
+	first := newTestPlugin(server.URL)
+	first.RateLimitGroup = "test-group"
+	first.RateLimitWeight = 2*exchangeInfoWeight + priceWeight
+	require.NoError(t, first.Init())
+
+	second := newTestPlugin(server.URL)
+	second.RateLimitGroup = "test-group"
+	require.NoError(t, second.Init())
+	require.Same(t, first.budget, second.budget)
+
+	var acc testutil.Accumulator
+	require.NoError(t, first.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.NoError(t, second.Gather(&acc))
+	require.Len(t, acc.Errors, 1)
+	require.ErrorIs(t, acc.Errors[0], errBudgetExhausted)
+	require.Len(t, acc.GetTelegrafMetrics(), 1)
+}
+
+// klinesHandler generates one-minute klines for the requested time-range
+// honoring the limit of the request.
+func klinesHandler(t *testing.T, requests *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		q := r.URL.Query()
+		start, errStart := strconv.ParseInt(q.Get("startTime"), 10, 64)
+		end, errEnd := strconv.ParseInt(q.Get("endTime"), 10, 64)
+		limit, errLimit := strconv.Atoi(q.Get("limit"))
+		if err := errors.Join(errStart, errEnd, errLimit); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			t.Error(err)
+			return
+		}
+
+		// Align the start to the next full minute
+		start = (start + 59999) / 60000 * 60000
+		klines := make([]string, 0, limit)
+		for ts := start; ts <= end && len(klines) < limit; ts += 60000 {
+			klines = append(klines, fmt.Sprintf(`[%d,"1.0","2.0","0.5","1.5","10.0",%d,"15.0",3,"4.0","6.0","0"]`, ts, ts+59999))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte("[" + strings.Join(klines, ",") + "]")); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestInitInvalidExport(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		end      string
+		interval string
+		expected string
+	}{
+		{
+			name:     "invalid start",
+			start:    "yesterday",
+			expected: `invalid export_start "yesterday"`,
+		},
+		{
+			name:     "end without start",
+			end:      "2025-01-01T00:00:00Z",
+			expected: "export_end requires export_start to be set",
+		},
+		{
+			name:     "end before start",
+			start:    "2025-01-02T00:00:00Z",
+			end:      "2025-01-01T00:00:00Z",
+			expected: "export_end must not be before export_start",
+		},
+		{
+			name:     "invalid interval",
+			start:    "2025-01-01T00:00:00Z",
+			interval: "2m",
+			expected: `invalid export_interval "2m"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := newTestPlugin("http://localhost")
+			plugin.ExportStart = tt.start
+			plugin.ExportEnd = tt.end
+			plugin.ExportInterval = tt.interval
+			require.ErrorContains(t, plugin.Init(), tt.expected)
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle("/klines", klinesHandler(t, &requests))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// The range covers 1500 klines requiring two pages
+	plugin := newTestPlugin(server.URL)
+	plugin.ExportStart = "2025-01-01T00:00:00Z"
+	plugin.ExportEnd = "2025-01-02T00:59:59Z"
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 2, requests)
+
+	klines := make([]telegraf.Metric, 0, 1500)
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_kline" {
+			klines = append(klines, m)
+		}
+	}
+	require.Len(t, klines, 1500)
+
+	expected := metric.New(
+		"binance_kline",
+		map[string]string{"base": "BTC", "quote": "EUR", "interval": "1m"},
+		map[string]interface{}{
+			"open":                   1.0,
+			"high":                   2.0,
+			"low":                    0.5,
+			"close":                  1.5,
+			"volume":                 10.0,
+			"quote_volume":           15.0,
+			"trades":                 int64(3),
+			"taker_buy_volume":       4.0,
+			"taker_buy_quote_volume": 6.0,
+		},
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	)
+	testutil.RequireMetricEqual(t, expected, klines[0])
+	require.Equal(t, time.Date(2025, 1, 2, 0, 59, 0, 0, time.UTC), klines[1499].Time().UTC())
+
+	// The export must only happen once
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Equal(t, 2, requests)
+	require.Len(t, acc.GetTelegrafMetrics(), 1)
+}
+
+// seriesHandler serves the records of a series with the given step between
+// first and last, honoring the start and end time as well as the limit
+func seriesHandler(t *testing.T, requests *int, first, last, step int64, record func(ts int64) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		q := r.URL.Query()
+		start, end := first, last
+		if v := q.Get("startTime"); v != "" {
+			ts, err := strconv.ParseInt(v, 10, 64)
+			require.NoError(t, err)
+			start = max(start, (ts+step-1)/step*step)
+		}
+		if v := q.Get("endTime"); v != "" {
+			ts, err := strconv.ParseInt(v, 10, 64)
+			require.NoError(t, err)
+			end = min(end, ts)
+		}
+		limit, err := strconv.Atoi(q.Get("limit"))
+		require.NoError(t, err)
+
+		records := make([]string, 0, limit)
+		for ts := start; ts <= end && len(records) < limit; ts += step {
+			records = append(records, record(ts))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte("[" + strings.Join(records, ",") + "]")); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestExportFuturesHistory(t *testing.T) {
+	rangeStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rangeEnd := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+
+	// The range covers 121 funding rates and 961 hourly periods of open
+	// interest requiring two pages each, klines are not of interest here
+	var funding, openInterest int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/klines", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("[]"))
+	})
+	mux.Handle("/fundingRate", seriesHandler(t, &funding, rangeStart.UnixMilli(), rangeEnd.UnixMilli(), 8*time.Hour.Milliseconds(),
+		func(ts int64) string {
+			return fmt.Sprintf(`{"symbol":"BTCUSDT","fundingTime":%d,"fundingRate":"0.0001","markPrice":"80000.0"}`, ts)
+		},
+	))
+	mux.Handle("/openInterestHist", seriesHandler(t, &openInterest, rangeStart.UnixMilli(), rangeEnd.UnixMilli(), time.Hour.Milliseconds(),
+		func(ts int64) string {
+			return fmt.Sprintf(`{"symbol":"BTCUSDT","sumOpenInterest":"80000.0","sumOpenInterestValue":"6400000000.0","timestamp":%d}`, ts)
+		},
+	))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin("")
+	plugin.QuoteAsset = "USDT"
+	plugin.Market = "usdm_futures"
+	plugin.FundingRates = true
+	plugin.OpenInterestPeriod = "1h"
+	plugin.ExportStart = rangeStart.Format(time.RFC3339)
+	plugin.ExportEnd = rangeEnd.Format(time.RFC3339)
+	plugin.futuresURL = server.URL
+	plugin.futuresDataURL = server.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	settled := make(map[int64]bool)
+	periods := make(map[int64]bool)
+	for _, m := range acc.GetTelegrafMetrics() {
+		switch m.Name() {
+		case "binance_funding":
+			if m.Tags()["type"] != "settled" {
+				continue
+			}
+			require.Falsef(t, settled[m.Time().UnixMilli()], "duplicate funding rate at %s", m.Time())
+			settled[m.Time().UnixMilli()] = true
+		case "binance_open_interest":
+			require.Equal(t, "1h", m.Tags()["period"])
+			require.Falsef(t, periods[m.Time().UnixMilli()], "duplicate open interest at %s", m.Time())
+			periods[m.Time().UnixMilli()] = true
+		}
+	}
+	require.Len(t, settled, 121)
+	require.Len(t, periods, 961)
+	require.Contains(t, settled, rangeStart.UnixMilli())
+	require.Contains(t, settled, rangeEnd.UnixMilli())
+
+	// Two pages each for the export and one query each for the collection
+	// continuing after the exported records
+	require.Equal(t, 3, funding)
+	require.Equal(t, 3, openInterest)
+	require.Equal(t, rangeEnd.UnixMilli(), plugin.state.LastFunding["BTCUSDT"])
+}
+
+func TestBackfill(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle("/klines", klinesHandler(t, &requests))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.BackfillDuration = config.Duration(time.Hour)
+	plugin.KlineInterval = "1m"
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	// Expect the klines of the last hour without any duplicates between the
+	// backfilled and the collected klines
+	seen := make(map[time.Time]bool)
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() != "binance_kline" {
+			continue
+		}
+		require.Falsef(t, seen[m.Time()], "duplicate kline at %s", m.Time())
+		seen[m.Time()] = true
+		require.True(t, m.Time().After(time.Now().Add(-time.Hour-time.Minute)))
+	}
+	require.GreaterOrEqual(t, len(seen), 59)
+	require.LessOrEqual(t, len(seen), 60)
+	require.Contains(t, plugin.state.LastKline, "BTCEUR/1m")
+
+	// The backfill must only happen once
+	acc.ClearMetrics()
+	requests = 0
+	require.NoError(t, plugin.Gather(&acc))
+	require.Equal(t, 1, requests)
+}
+
+func TestBackfillRestart(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle("/klines", klinesHandler(t, &requests))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// Pretend the previous run reported the klines until ten minutes ago
+	last := time.Now().Add(-10 * time.Minute).Truncate(time.Minute).Add(-time.Millisecond)
+	plugin := newTestPlugin(server.URL)
+	plugin.BackfillDuration = config.Duration(time.Hour)
+	plugin.KlineInterval = "1m"
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.SetState(state{LastKline: map[string]time.Time{"BTCEUR/1m": last}}))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	// Expect only the klines closed after the persisted one
+	seen := make(map[time.Time]bool)
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() != "binance_kline" {
+			continue
+		}
+		require.Falsef(t, seen[m.Time()], "duplicate kline at %s", m.Time())
+		seen[m.Time()] = true
+		require.True(t, m.Time().After(last))
+	}
+	require.GreaterOrEqual(t, len(seen), 9)
+	require.LessOrEqual(t, len(seen), 10)
+
+	s, ok := plugin.GetState().(state)
+	require.True(t, ok)
+	require.True(t, s.LastKline["BTCEUR/1m"].After(last))
+}
+
+func TestInitInvalidBackfill(t *testing.T) {
+	plugin := newTestPlugin("http://localhost")
+	plugin.BackfillDuration = config.Duration(-time.Hour)
+	require.ErrorContains(t, plugin.Init(), "backfill_duration must not be negative")
+
+	plugin = newTestPlugin("http://localhost")
+	plugin.BackfillDuration = config.Duration(time.Hour)
+	plugin.ExportStart = "2025-01-01T00:00:00Z"
+	require.ErrorContains(t, plugin.Init(), "backfill_duration cannot be combined with export_start")
+}
+
+func TestGapFill(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle("/klines", klinesHandler(t, &requests))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.GapFill = true
+	plugin.GapFillThreshold = config.Duration(2 * time.Minute)
+	plugin.GapFillMaxAge = config.Duration(time.Hour)
+	require.NoError(t, plugin.Init())
+
+	// Pretend the last price was emitted ten minutes ago in a previous run
+	last := time.Now().Add(-10 * time.Minute)
+	require.NoError(t, plugin.SetState(state{LastPrice: map[string]time.Time{"BTCEUR": last}}))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 1, requests)
+
+	// Expect one price per minute of the gap plus the current price
+	metrics := acc.GetTelegrafMetrics()
+	require.GreaterOrEqual(t, len(metrics), 10)
+	for _, m := range metrics[:len(metrics)-1] {
+		require.True(t, m.Time().After(last))
+		require.Zero(t, m.Time().Second())
+		price, found := m.GetField("price")
+		require.True(t, found)
+		require.InDelta(t, 1.5, price, 1e-9)
+	}
+	price, found := metrics[len(metrics)-1].GetField("price")
+	require.True(t, found)
+	require.InDelta(t, 76543.21, price, 1e-9)
+
+	// The state must contain the time of the current price and no gap should
+	// be detected in the next cycle
+	s, ok := plugin.GetState().(state)
+	require.True(t, ok)
+	require.True(t, s.LastPrice["BTCEUR"].After(last))
+
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Equal(t, 1, requests)
+	require.Len(t, acc.GetTelegrafMetrics(), 1)
+}
+
+func TestKlines(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle("/klines", klinesHandler(t, &requests))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.KlineInterval = "1m"
+	require.NoError(t, plugin.Init())
+
+	// The first cycle must only emit the most recently closed kline
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 1, requests)
+
+	var klines []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_kline" {
+			klines = append(klines, m)
+		}
+	}
+	require.Len(t, klines, 1)
+	require.Equal(t, "1m", klines[0].Tags()["interval"])
+	require.Zero(t, klines[0].Time().Second())
+	require.True(t, klines[0].Time().Add(time.Minute).Before(time.Now()))
+
+	// Later cycles must emit all klines closed since the last one
+	plugin.state.LastKline["BTCEUR/1m"] = time.Now().Add(-5 * time.Minute).Truncate(time.Minute).Add(-time.Millisecond)
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 2, requests)
+	klines = klines[:0]
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_kline" {
+			klines = append(klines, m)
+		}
+	}
+	require.Len(t, klines, 5)
+}
+
+func TestKlinesRestart(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle("/klines", klinesHandler(t, &requests))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.KlineInterval = "1m"
+	require.NoError(t, plugin.Init())
+
+	// The klines closed while the plugin was not running must be emitted
+	last := time.Now().Add(-5 * time.Minute).Truncate(time.Minute).Add(-time.Millisecond)
+	require.NoError(t, plugin.SetState(state{LastKline: map[string]time.Time{"BTCEUR/1m": last}}))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 1, requests)
+
+	var klines []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_kline" {
+			klines = append(klines, m)
+		}
+	}
+	require.Len(t, klines, 5)
+	require.Equal(t, last.Add(time.Millisecond), klines[0].Time())
+
+	s, ok := plugin.GetState().(state)
+	require.True(t, ok)
+	require.Equal(t, klines[4].Time().Add(time.Minute-time.Millisecond), s.LastKline["BTCEUR/1m"])
+}
+
+func TestKlinesCloseTimestamp(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle("/klines", klinesHandler(t, &requests))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.KlineInterval = "1m"
+	plugin.KlineTimestamp = "close"
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 1, requests)
+
+	var found bool
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_kline" {
+			found = true
+			require.Equal(t, 59, m.Time().Second())
+			require.Equal(t, 999*time.Millisecond, time.Duration(m.Time().Nanosecond()))
+		}
+	}
+	require.True(t, found)
+}
+
+func TestInitInvalidKlines(t *testing.T) {
+	plugin := newTestPlugin("http://localhost")
+	plugin.KlineInterval = "2m"
+	require.ErrorContains(t, plugin.Init(), `invalid kline_interval "2m"`)
+
+	plugin = newTestPlugin("http://localhost")
+	plugin.KlineInterval = "1m"
+	plugin.KlineTimestamp = "middle"
+	require.ErrorContains(t, plugin.Init(), `invalid kline_timestamp "middle"`)
+}
+
+func TestQuoteAssets(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	// The non-existing BTCFOO pair must be skipped, reporting the error
+	// responses for it and its inverse with the first gather cycle
+	plugin := newTestPlugin(server.URL)
+	plugin.QuoteAsset = ""
+	plugin.QuoteAssets = []string{"USDT", "FOO", "EUR"}
+	require.NoError(t, plugin.Init())
+	require.Len(t, plugin.pairs, 2)
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "USDT"},
+			map[string]interface{}{"price": 82345.67},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{"price": 76543.21},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_api_error",
+			map[string]string{"endpoint": "/exchangeInfo", "code": "-1121", "category": "invalid_symbol"},
+			map[string]interface{}{"message": "Invalid symbol.", "status_code": int64(400)},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_api_error",
+			map[string]string{"endpoint": "/exchangeInfo", "code": "-1121", "category": "invalid_symbol"},
+			map[string]interface{}{"message": "Invalid symbol.", "status_code": int64(400)},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestInversePair(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	// USDTEUR is not listed, so the inverse price of EURUSDT is reported
+	plugin := newTestPlugin(server.URL)
+	plugin.BaseAsset = "USDT"
+	plugin.QuoteAsset = "EUR"
+	require.NoError(t, plugin.Init())
+	require.Len(t, plugin.pairs, 1)
+	require.Equal(t, "EURUSDT", plugin.pairs[0].symbol)
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance",
+			map[string]string{"base": "USDT", "quote": "EUR", "inverted": "true"},
+			map[string]interface{}{"price": 1 / 1.085},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), cmpopts.EquateApprox(0, 1e-9))
+}
+
+func TestSymbol(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	// The base and quote tags must be derived from the exchange information
+	plugin := newTestPlugin(server.URL)
+	plugin.Symbol = "btcusdt"
+	plugin.BaseAsset = ""
+	plugin.QuoteAsset = ""
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "USDT"},
+			map[string]interface{}{"price": 82345.67},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestSymbols(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	// All pairs are gathered in a single price request
+	plugin := newTestPlugin(server.URL)
+	plugin.Symbols = []string{"btcusdt", "BTCEUR"}
+	plugin.BaseAsset = ""
+	plugin.QuoteAsset = ""
+	require.NoError(t, plugin.Init())
+	require.Len(t, plugin.pairs, 2)
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "USDT"},
+			map[string]interface{}{"price": 82345.67},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{"price": 76543.21},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestAllSymbols(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	// Symbols not trading are skipped
+	plugin := newTestPlugin(server.URL)
+	plugin.AllSymbols = true
+	plugin.BaseAsset = ""
+	plugin.QuoteAsset = ""
+	require.NoError(t, plugin.Init())
+	require.Len(t, plugin.pairs, 2)
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{"price": 76543.21},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "USDT"},
+			map[string]interface{}{"price": 82345.67},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestInitAllSymbolsExclusive(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.AllSymbols = true
+	require.ErrorContains(t, plugin.Init(), "all_symbols cannot be combined with symbol, symbols or base_asset")
+}
+
+func TestTopSymbolsByVolume(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	// The volume of BTCEUR exceeds the one of BTCUSDT only in US dollars and
+	// LUNAUSDT is not trading
+	plugin := newTestPlugin(server.URL)
+	plugin.BaseAsset = ""
+	plugin.QuoteAsset = ""
+	plugin.TopSymbolsByVolume = 1
+	plugin.SymbolsRefresh = config.Duration(time.Hour)
+	require.NoError(t, plugin.Init())
+	require.Len(t, plugin.pairs, 1)
+	require.Equal(t, "BTCEUR", plugin.pairs[0].symbol)
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{"price": 76543.21},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+
+	// The selection must be refreshed after the refresh interval
+	plugin.TopSymbolsByVolume = 5
+	plugin.pairsQueried = time.Now().Add(-time.Hour)
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected = append(expected, metric.New(
+		"binance",
+		map[string]string{"base": "BTC", "quote": "USDT"},
+		map[string]interface{}{"price": 82345.67},
+		time.Unix(0, 0),
+	))
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestInitInvalidTopSymbolsByVolume(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.TopSymbolsByVolume = 10
+	require.ErrorContains(t, plugin.Init(), "top_symbols_by_volume cannot be combined with all_symbols, symbol, symbols or base_asset")
+
+	plugin = newTestPlugin("")
+	plugin.BaseAsset = ""
+	plugin.QuoteAsset = ""
+	plugin.TopSymbolsByVolume = -1
+	require.ErrorContains(t, plugin.Init(), "top_symbols_by_volume must not be negative")
+}
+
+func TestQuoteSelection(t *testing.T) {
+	info := `{"symbols":[
+		{"symbol":"BTCUSDT","status":"TRADING","baseAsset":"BTC","quoteAsset":"USDT"},
+		{"symbol":"ETHUSDT","status":"TRADING","baseAsset":"ETH","quoteAsset":"USDT"},
+		{"symbol":"ETHUPUSDT","status":"TRADING","baseAsset":"ETHUP","quoteAsset":"USDT"},
+		{"symbol":"ETHDOWNUSDT","status":"TRADING","baseAsset":"ETHDOWN","quoteAsset":"USDT"},
+		{"symbol":"LUNAUSDT","status":"BREAK","baseAsset":"LUNA","quoteAsset":"USDT"},
+		{"symbol":"BTCEUR","status":"TRADING","baseAsset":"BTC","quoteAsset":"EUR"}
+	]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != exchangeEndpoint || r.URL.RawQuery != "" {
+			w.WriteHeader(http.StatusNotFound)
+			t.Errorf("unexpected request %s", r.URL)
+			return
+		}
+		if _, err := w.Write([]byte(info)); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		quotes   []string
+		include  []string
+		exclude  []string
+		expected []string
+	}{
+		{
+			name:     "quote asset",
+			quotes:   []string{"usdt"},
+			expected: []string{"BTCUSDT", "ETHUSDT", "ETHUPUSDT", "ETHDOWNUSDT"},
+		},
+		{
+			name:     "exclude leveraged tokens",
+			quotes:   []string{"USDT"},
+			exclude:  []string{"UPUSDT$", "DOWNUSDT$"},
+			expected: []string{"BTCUSDT", "ETHUSDT"},
+		},
+		{
+			name:     "include",
+			quotes:   []string{"USDT", "EUR"},
+			include:  []string{"^BTC"},
+			expected: []string{"BTCUSDT", "BTCEUR"},
+		},
+		{
+			name:     "include and exclude",
+			quotes:   []string{"USDT"},
+			include:  []string{"^ETH"},
+			exclude:  []string{"DOWN"},
+			expected: []string{"ETHUSDT", "ETHUPUSDT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := newTestPlugin(server.URL)
+			plugin.BaseAsset = ""
+			plugin.QuoteAsset = ""
+			plugin.QuoteAssets = tt.quotes
+			plugin.SymbolInclude = tt.include
+			plugin.SymbolExclude = tt.exclude
+			require.NoError(t, plugin.Init())
+
+			symbols := make([]string, 0, len(plugin.pairs))
+			for _, p := range plugin.pairs {
+				symbols = append(symbols, p.symbol)
+			}
+			require.Equal(t, tt.expected, symbols)
+		})
+	}
+}
+
+func TestInitInvalidSymbolPatterns(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.SymbolExclude = []string{"UP$"}
+	require.ErrorContains(t, plugin.Init(), "symbol_include and symbol_exclude require all_symbols, top_symbols_by_volume or quote assets without base_asset")
+
+	plugin = newTestPlugin("")
+	plugin.BaseAsset = ""
+	plugin.SymbolInclude = []string{"(BTC"}
+	require.ErrorContains(t, plugin.Init(), "invalid symbol_include pattern \"(BTC\"")
+}
+
+func TestSymbolsFile(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	filename := filepath.Join(t.TempDir(), "symbols.txt")
+	require.NoError(t, os.WriteFile(filename, []byte("# watched pairs\nBTCEUR, btcusdt\n"), 0o600))
+
+	plugin := newTestPlugin(server.URL)
+	plugin.BaseAsset = ""
+	plugin.QuoteAsset = ""
+	plugin.SymbolsFile = filename
+	plugin.SymbolsRefresh = config.Duration(time.Hour)
+	require.NoError(t, plugin.Init())
+	require.Len(t, plugin.pairs, 2)
+
+	// The changed file must be picked up after the refresh interval
+	require.NoError(t, os.WriteFile(filename, []byte("BTCEUR\n"), 0o600))
+	plugin.pairsQueried = time.Now().Add(-time.Hour)
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{"price": 76543.21},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+
+	// The previous pairs are kept if the file cannot be read
+	require.NoError(t, os.Remove(filename))
+	plugin.pairsQueried = time.Now().Add(-time.Hour)
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, acc.Errors, 1)
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestSymbolsURL(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	symbolsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if _, err := w.Write([]byte("BTCUSDT\n")); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer symbolsServer.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.SymbolsFile = symbolsServer.URL + "/symbols.txt"
+	require.NoError(t, plugin.Init())
+
+	// The symbols of the file extend the configured ones
+	symbols := make([]string, 0, len(plugin.pairs))
+	for _, p := range plugin.pairs {
+		symbols = append(symbols, p.symbol)
+	}
+	require.Equal(t, []string{"BTCUSDT", "BTCEUR"}, symbols)
+}
+
+func TestInitInvalidSymbolsFile(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.BaseAsset = ""
+	plugin.QuoteAsset = ""
+	plugin.AllSymbols = true
+	plugin.SymbolsFile = "symbols.txt"
+	require.ErrorContains(t, plugin.Init(), "symbols_file cannot be combined with all_symbols")
+}
+
+func TestP2P(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	p2pServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req p2pRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			t.Error(err)
+			return
+		}
+		if len(req.PayTypes) != 1 || req.PayTypes[0] != "BANK" {
+			w.WriteHeader(http.StatusBadRequest)
+			t.Errorf("unexpected payment methods %v", req.PayTypes)
+			return
+		}
+		name := fmt.Sprintf("p2p_%s_%s_%s.json", req.Asset, req.Fiat, req.TradeType)
+		buf, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			t.Error(err)
+			return
+		}
+		if _, err := w.Write(buf); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer p2pServer.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.p2pURL = p2pServer.URL
+	plugin.P2P = []*p2pMarket{{Asset: "usdt", Fiat: "ngn", PaymentMethods: []string{"BANK"}}}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_p2p",
+			map[string]string{"asset": "USDT", "fiat": "NGN", "side": "buy", "payment_methods": "BANK"},
+			map[string]interface{}{
+				"ads":               3,
+				"best_price":        1580.0,
+				"median_price":      1582.5,
+				"tradable_quantity": 1600.0,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_p2p",
+			map[string]string{"asset": "USDT", "fiat": "NGN", "side": "sell", "payment_methods": "BANK"},
+			map[string]interface{}{
+				"ads":               2,
+				"best_price":        1575.0,
+				"median_price":      1572.5,
+				"tradable_quantity": 750.0,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{"price": 76543.21},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestAssetsFromTitle(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected []string
+	}{
+		{
+			title:    "Binance Will List Pepe (PEPE) with Seed Tag Applied",
+			expected: []string{"PEPE"},
+		},
+		{
+			title:    "Binance Will Add Arbitrum (ARB) and Sui (SUI) on Earn",
+			expected: []string{"ARB", "SUI"},
+		},
+		{
+			title:    "Binance Will Delist ANT, MULTI and VAI on 2025-03-20",
+			expected: []string{"ANT", "MULTI", "VAI"},
+		},
+		{
+			title: "Binance Will Delist and Cease Trading on Multiple Spot Trading Pairs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			require.Equal(t, tt.expected, assetsFromTitle(tt.title))
+		})
+	}
+}
+
+func TestAnnouncements(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	var requests int
+	cmsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		name := "announcements_" + r.URL.Query().Get("catalogId") + ".json"
+		buf, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			t.Error(err)
+			return
+		}
+		if _, err := w.Write(buf); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer cmsServer.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.announcementsURL = cmsServer.URL
+	plugin.Announcements = []string{"new_listing", "delisting"}
+	plugin.AnnouncementsRefresh = config.Duration(time.Hour)
+	require.NoError(t, plugin.Init())
+
+	// Pretend the older listing was already reported in a previous run
+	require.NoError(t, plugin.SetState(state{LastAnnouncement: map[string]int64{"new_listing": 1741644000000}}))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 2, requests)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_announcement",
+			map[string]string{"category": "new_listing"},
+			map[string]interface{}{
+				"id":     int64(226731),
+				"code":   "6a2f9c1b",
+				"title":  "Binance Will List Pepe (PEPE) with Seed Tag Applied",
+				"assets": "PEPE",
+			},
+			time.UnixMilli(1741730400000),
+		),
+		metric.New(
+			"binance_announcement",
+			map[string]string{"category": "delisting"},
+			map[string]interface{}{
+				"id":     int64(226700),
+				"code":   "9e0b21cd",
+				"title":  "Binance Will Delist ANT, MULTI and VAI on 2025-03-20",
+				"assets": "ANT,MULTI,VAI",
+			},
+			time.UnixMilli(1741687200000),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_announcement" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+
+	// Announcements must neither be queried again within the refresh
+	// interval nor reported twice
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Equal(t, 2, requests)
+	plugin.announcementsQueried = time.Time{}
+	require.NoError(t, plugin.Gather(&acc))
+	require.Equal(t, 4, requests)
+	for _, m := range acc.GetTelegrafMetrics() {
+		require.NotEqual(t, "binance_announcement", m.Name())
+	}
+}
+
+func TestSymbolWarnings(t *testing.T) {
+	testdata := testdataHandler(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Halt trading of the pair after the plugin resolved it
+		if r.URL.Path == exchangeEndpoint && r.URL.Query().Has("symbols") {
+			if _, err := w.Write([]byte(`{"symbols":[{"symbol":"BTCEUR","status":"BREAK","baseAsset":"BTC","quoteAsset":"EUR"}]}`)); err != nil {
+				t.Error(err)
+			}
+			return
+		}
+		testdata(w, r)
+	}))
+	defer server.Close()
+
+	cmsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := "announcements_" + r.URL.Query().Get("catalogId") + ".json"
+		buf, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			t.Error(err)
+			return
+		}
+		if _, err := w.Write(buf); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer cmsServer.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.announcementsURL = cmsServer.URL
+	plugin.Announcements = []string{"delisting"}
+	plugin.AnnouncementsRefresh = config.Duration(time.Hour)
+	plugin.SymbolWarnings = true
+	plugin.SymbolStatusRefresh = config.Duration(time.Hour)
+	require.NoError(t, plugin.Init())
+
+	// Track a pair of an asset mentioned in the delisting announcement
+	plugin.pairs = append(plugin.pairs, newPair(symbolInfo{Symbol: "ANTUSDT", Status: statusTrading, BaseAsset: "ANT", QuoteAsset: "USDT"}))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.gatherSymbolStatus(&acc))
+	plugin.gatherAnnouncements(&acc)
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_symbol_warning",
+			map[string]string{"base": "BTC", "quote": "EUR", "reason": "status"},
+			map[string]interface{}{
+				"status":          "BREAK",
+				"previous_status": "TRADING",
+				"trading":         false,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_symbol_warning",
+			map[string]string{"base": "ANT", "quote": "USDT", "reason": "delisting"},
+			map[string]interface{}{
+				"announcement_id": int64(226700),
+				"title":           "Binance Will Delist ANT, MULTI and VAI on 2025-03-20",
+				"delisting_time":  int64(1742428800000),
+				"lead_time":       int64(741600),
+			},
+			time.UnixMilli(1741687200000),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_symbol_warning" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), testutil.SortMetrics())
+
+	// Unchanged statuses are not reported again
+	plugin.statusQueried = time.Now().Add(-2 * time.Hour)
+	acc.ClearMetrics()
+	require.NoError(t, plugin.gatherSymbolStatus(&acc))
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
+
+func TestSymbolWarningsDelisted(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.SymbolWarnings = true
+	plugin.SymbolStatusRefresh = config.Duration(time.Hour)
+	require.NoError(t, plugin.Init())
+
+	// Track a pair removed from the exchange, invalidating the whole query of
+	// the trading status
+	plugin.pairs = append(plugin.pairs, newPair(symbolInfo{Symbol: "ANTUSDT", Status: statusTrading, BaseAsset: "ANT", QuoteAsset: "USDT"}))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_symbol_warning",
+			map[string]string{"base": "ANT", "quote": "USDT", "reason": "status"},
+			map[string]interface{}{
+				"status":          "DELISTED",
+				"previous_status": "TRADING",
+				"trading":         false,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{"price": 76543.21},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() != "binance_api_error" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+	require.Len(t, plugin.pairs, 1)
+}
+
+func TestExchangeInfoSummary(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.ExchangeInfoSummary = true
+	plugin.ExchangeInfoRefresh = config.Duration(time.Hour)
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_exchange_info",
+			map[string]string{},
+			map[string]interface{}{"symbols": 3, "trading": 2, "break": 1, "halt": 0},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_exchange_info_quote",
+			map[string]string{"quote": "EUR"},
+			map[string]interface{}{"symbols": 1, "trading": 1, "break": 0, "halt": 0},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_exchange_info_quote",
+			map[string]string{"quote": "USDT"},
+			map[string]interface{}{"symbols": 2, "trading": 1, "break": 1, "halt": 0},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if strings.HasPrefix(m.Name(), "binance_exchange_info") {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), testutil.SortMetrics())
+
+	// The summary must not be refreshed before the refresh interval passed
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Len(t, acc.GetTelegrafMetrics(), 1)
+}
+
+func TestStatusTag(t *testing.T) {
+	testdata := testdataHandler(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Halt trading of the pair after the plugin resolved it
+		if r.URL.Path == exchangeEndpoint && r.URL.Query().Has("symbols") {
+			if _, err := w.Write([]byte(`{"symbols":[{"symbol":"BTCEUR","status":"HALT","baseAsset":"BTC","quoteAsset":"EUR"}]}`)); err != nil {
+				t.Error(err)
+			}
+			return
+		}
+		testdata(w, r)
+	}))
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.StatusTag = true
+	plugin.SymbolStatusRefresh = config.Duration(time.Hour)
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	// Only the price must be emitted without symbol warnings enabled
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "EUR", "status": "HALT"},
+			map[string]interface{}{"price": 76543.21},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestSymbolFilters(t *testing.T) {
+	testdata := testdataHandler(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Answer the status refresh with the full information of the pair
+		if r.URL.Path == exchangeEndpoint && r.URL.Query().Has("symbols") {
+			http.ServeFile(w, r, filepath.Join("testdata", "exchangeInfo_BTCEUR.json"))
+			return
+		}
+		testdata(w, r)
+	}))
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.SymbolFilters = true
+	plugin.SymbolStatusRefresh = config.Duration(time.Hour)
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_symbol_filters",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{
+				"min_price":    0.01,
+				"max_price":    1000000.0,
+				"tick_size":    0.01,
+				"min_quantity": 0.00001,
+				"max_quantity": 9000.0,
+				"step_size":    0.00001,
+				"min_notional": 5.0,
+				"max_notional": 9000000.0,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_symbol_filters" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+
+	// The filters must only be refreshed once per refresh interval
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, acc.GetTelegrafMetrics(), 1)
+}
+
+func TestIndexInfo(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.futuresURL = server.URL
+	plugin.IndexInfo = []string{"DEFIUSDT"}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	ts := time.UnixMilli(1741735124077)
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_index_component",
+			map[string]string{"index": "DEFIUSDT", "base": "UNI", "quote": "USDT"},
+			map[string]interface{}{"weight_in_quantity": 5.32174518, "weight_in_percentage": 0.282134},
+			ts,
+		),
+		metric.New(
+			"binance_index_component",
+			map[string]string{"index": "DEFIUSDT", "base": "AAVE", "quote": "USDT"},
+			map[string]interface{}{"weight_in_quantity": 0.17283219, "weight_in_percentage": 0.361159},
+			ts,
+		),
+		metric.New(
+			"binance_index_component",
+			map[string]string{"index": "DEFIUSDT", "base": "MKR", "quote": "USDT"},
+			map[string]interface{}{"weight_in_quantity": 0.02251432, "weight_in_percentage": 0.356707},
+			ts,
+		),
+		metric.New(
+			"binance_index",
+			map[string]string{"index": "DEFIUSDT"},
+			map[string]interface{}{"components": 3, "changed": false},
+			ts,
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if strings.HasPrefix(m.Name(), "binance_index") {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+
+	// Simulate a change in the composition
+	plugin.indexCompositions["DEFIUSDT"] = "UNI=1.0"
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	changed, found := acc.BoolField("binance_index", "changed")
+	require.True(t, found)
+	require.True(t, changed)
+}
+
+func Test24hStats(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.Collect24hStats = true
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_24h",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{
+				"open":                 77123.45,
+				"high":                 78456.78,
+				"low":                  75432.1,
+				"close":                76543.21,
+				"volume":               1287.65432,
+				"quote_volume":         98765432.123456,
+				"price_change":         -580.24,
+				"price_change_percent": -0.752,
+				"weighted_avg_price":   76701.23456,
+				"trades":               int64(154321),
+				"open_time":            int64(1741648723999),
+				"close_time":           int64(1741735123999),
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_24h" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func Test24hStatsNotionalUSD(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.Collect24hStats = true
+	plugin.NotionalUSD = true
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	var found bool
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() != "binance_24h" {
+			continue
+		}
+		found = true
+		notional, ok := m.GetField("notional_usd")
+		require.True(t, ok)
+		require.InDelta(t, 98765432.123456*1.085, notional, 1e-6)
+	}
+	require.True(t, found)
+}
+
+func TestUSDPrices(t *testing.T) {
+	testdata := testdataHandler(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != priceEndpoint {
+			testdata(w, r)
+			return
+		}
+		body := `[{"symbol":"BTCUSDT","price":"82345.67"},{"symbol":"USDTTRY","price":"36.5"},` +
+			`{"symbol":"USDTEUR","price":"0.9"},{"symbol":"EURUSDT","price":"1.085"},{"symbol":"BTCEUR","price":"76543.21"}]`
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	require.NoError(t, plugin.Init())
+
+	prices, err := plugin.usdPrices(t.Context())
+	require.NoError(t, err)
+
+	// The direct pair takes precedence over the inverse one
+	expected := map[string]float64{"USDT": 1, "BTC": 82345.67, "TRY": 1 / 36.5, "EUR": 1.085}
+	require.InDeltaMapValues(t, expected, prices, 1e-9)
+}
+
+func TestConvertTo(t *testing.T) {
+	tests := []struct {
+		name      string
+		convertTo string
+		expected  map[string]float64
+	}{
+		{
+			name:      "multiply by cross pair",
+			convertTo: "usdt",
+			expected:  map[string]float64{"EUR": 76543.21 * 1.085, "USDT": 82345.67},
+		},
+		{
+			name:      "divide by cross pair",
+			convertTo: "eur",
+			expected:  map[string]float64{"EUR": 76543.21, "USDT": 82345.67 / 1.085},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			defer server.Close()
+
+			plugin := newTestPlugin(server.URL)
+			plugin.QuoteAsset = ""
+			plugin.QuoteAssets = []string{"EUR", "USDT"}
+			plugin.ConvertTo = tt.convertTo
+			require.NoError(t, plugin.Init())
+
+			var acc testutil.Accumulator
+			require.NoError(t, plugin.Gather(&acc))
+			require.Empty(t, acc.Errors)
+
+			prices := map[string]float64{"EUR": 76543.21, "USDT": 82345.67}
+			expected := make([]telegraf.Metric, 0, len(prices))
+			for quote, price := range prices {
+				expected = append(expected, metric.New(
+					"binance",
+					map[string]string{"base": "BTC", "quote": quote, "convert_quote": strings.ToUpper(tt.convertTo)},
+					map[string]interface{}{"price": price, "price_converted": tt.expected[quote]},
+					time.Unix(0, 0),
+				))
+			}
+			// Probing the candidate cross pairs reports the unlisted ones as API errors
+			var actual []telegraf.Metric
+			for _, m := range acc.GetTelegrafMetrics() {
+				if m.Name() == "binance" {
+					actual = append(actual, m)
+				}
+			}
+			testutil.RequireMetricsEqual(t, expected, actual,
+				testutil.IgnoreTime(), testutil.SortMetrics(), cmpopts.EquateApprox(0, 1e-9))
+		})
+	}
+}
+
+func TestConvertToWithoutCrossPair(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.ConvertTo = "TRY"
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	// Prices without a cross pair are reported unconverted
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{"price": 76543.21},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestRollingWindows(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.RollingWindows = []string{"1h", "4h"}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := make([]telegraf.Metric, 0, 2)
+	for _, window := range []string{"1h", "4h"} {
+		expected = append(expected, metric.New(
+			"binance_rolling_window",
+			map[string]string{"base": "BTC", "quote": "EUR", "window": window},
+			map[string]interface{}{
+				"open":                 76222.11,
+				"high":                 76600.0,
+				"low":                  76111.11,
+				"close":                76543.21,
+				"volume":               98.76543,
+				"quote_volume":         7547655.4321,
+				"price_change":         321.1,
+				"price_change_percent": 0.421,
+				"weighted_avg_price":   76420.98765,
+				"trades":               int64(14321),
+				"open_time":            int64(1741720724000),
+				"close_time":           int64(1741735123999),
+			},
+			time.Unix(0, 0),
+		))
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_rolling_window" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestInitInvalidRollingWindow(t *testing.T) {
+	for _, window := range []string{"", "0m", "60m", "24h", "8d", "1w", "4H"} {
+		t.Run(window, func(t *testing.T) {
+			plugin := newTestPlugin("http://localhost")
+			plugin.RollingWindows = []string{window}
+			require.ErrorContains(t, plugin.Init(), "invalid rolling window")
+		})
+	}
+}
+
+func TestTicker24hWeight(t *testing.T) {
+	require.Equal(t, int64(2), ticker24hWeight(1))
+	require.Equal(t, int64(2), ticker24hWeight(20))
+	require.Equal(t, int64(40), ticker24hWeight(21))
+	require.Equal(t, int64(80), ticker24hWeight(101))
+	require.Equal(t, int64(80), ticker24hWeight(0))
+}
+
+func TestTradingDay(t *testing.T) {
+	var timezone string
+	mux := http.NewServeMux()
+	mux.Handle("/ticker/tradingDay", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timezone = r.URL.Query().Get("timeZone")
+		testdataHandler(t)(w, r)
+	}))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.TradingDay = true
+	plugin.TradingDayTimezone = "+05:45"
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, "05:45", timezone)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_trading_day",
+			map[string]string{"base": "BTC", "quote": "EUR", "timezone": "05:45"},
+			map[string]interface{}{
+				"open":                 77777.77,
+				"high":                 78123.45,
+				"low":                  75890.12,
+				"close":                76543.21,
+				"volume":               812.34567,
+				"quote_volume":         62560012.3456789,
+				"price_change":         -1234.56,
+				"price_change_percent": -1.588,
+				"weighted_avg_price":   77012.3456789,
+				"trades":               int64(100000),
+				"open_time":            int64(1741651200000),
+				"close_time":           int64(1741735123999),
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_trading_day" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestInitInvalidTimezone(t *testing.T) {
+	plugin := newTestPlugin("http://localhost")
+	plugin.TradingDayTimezone = "UTC"
+	require.ErrorContains(t, plugin.Init(), `invalid trading_day_timezone "UTC"`)
+}
+
+// tradesHandler generates trades with ascending identifiers up to the given
+// latest identifier honoring the fromId and limit of the request.
+func tradesHandler(t *testing.T, latest int64, requests *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if key := r.Header.Get("X-MBX-APIKEY"); key != "secret-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			t.Errorf("unexpected API key %q", key)
+			return
+		}
+		q := r.URL.Query()
+		limit, err := strconv.ParseInt(q.Get("limit"), 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			t.Error(err)
+			return
+		}
+		from := latest - limit + 1
+		if q.Has("fromId") {
+			if from, err = strconv.ParseInt(q.Get("fromId"), 10, 64); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				t.Error(err)
+				return
+			}
+		}
+
+		trades := make([]string, 0, limit)
+		for id := from; id <= latest && int64(len(trades)) < limit; id++ {
+			trades = append(trades, fmt.Sprintf(
+				`{"id":%d,"price":"100.0","qty":"0.5","quoteQty":"50.0","time":%d,"isBuyerMaker":%t,"isBestMatch":true}`,
+				id, 1741735124000+id, id%2 == 0,
+			))
+		}
+		if _, err := w.Write([]byte("[" + strings.Join(trades, ",") + "]")); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestHistoricalTrades(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle("/historicalTrades", tradesHandler(t, 5000, &requests))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("secret-key"))
+	plugin.HistoricalTrades = true
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.SetState(state{LastTradeID: map[string]int64{"BTCEUR": 2499}}))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 3, requests)
+
+	var trades []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_trade" {
+			trades = append(trades, m)
+		}
+	}
+	require.Len(t, trades, 2501)
+
+	expected := metric.New(
+		"binance_trade",
+		map[string]string{"base": "BTC", "quote": "EUR", "side": "sell"},
+		map[string]interface{}{
+			"id":             int64(2500),
+			"price":          100.0,
+			"quantity":       0.5,
+			"quote_quantity": 50.0,
+		},
+		time.UnixMilli(1741735124000+2500),
+	)
+	testutil.RequireMetricEqual(t, expected, trades[0])
+	require.Equal(t, "buy", trades[1].Tags()["side"])
+
+	s, ok := plugin.GetState().(state)
+	require.True(t, ok)
+	require.Equal(t, int64(5000), s.LastTradeID["BTCEUR"])
+}
+
+func TestHistoricalTradesBudget(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle("/historicalTrades", tradesHandler(t, 5000, &requests))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// Only allow a single page of trades in the first cycle and start with
+	// the most recent trades without prior state
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("secret-key"))
+	plugin.HistoricalTrades = true
+	plugin.RateLimitWeight = exchangeInfoWeight + priceWeight + historicalTradesWeight
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 1, requests)
+	require.Len(t, acc.GetTelegrafMetrics(), 1001)
+	require.Equal(t, int64(5000), plugin.state.LastTradeID["BTCEUR"])
+}
+
+func TestRecentTrades(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.RecentTrades = 4
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	quoteVolume := 76540.0*0.1 + 76542.0*0.2 + 76545.0*0.3 + 76538.0*0.4
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_recent_trades",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{
+				"trade_count":      4,
+				"buy_volume":       0.4,
+				"sell_volume":      0.6,
+				"min_price":        76538.0,
+				"max_price":        76545.0,
+				"vwap":             quoteVolume,
+				"first_trade_time": int64(1741735120000),
+				"last_trade_time":  int64(1741735123000),
+				"last_trade_id":    int64(1004),
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_recent_trades" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), cmpopts.EquateApprox(0, 1e-9))
+}
+
+func TestInitInvalidRecentTrades(t *testing.T) {
+	plugin := newTestPlugin("http://localhost")
+	plugin.RecentTrades = 1001
+	require.ErrorContains(t, plugin.Init(), "recent_trades 1001 not between 0 and 1000")
+}
+
+// aggTradesHandler generates aggregate trades with ascending identifiers up
+// to the given latest identifier honoring the fromId and limit of the request.
+// Each aggregate trade combines two trades.
+func aggTradesHandler(t *testing.T, latest int64, requests *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		q := r.URL.Query()
+		limit, err := strconv.ParseInt(q.Get("limit"), 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			t.Error(err)
+			return
+		}
+		from := latest - limit + 1
+		if q.Has("fromId") {
+			if from, err = strconv.ParseInt(q.Get("fromId"), 10, 64); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				t.Error(err)
+				return
+			}
+		}
+
+		trades := make([]string, 0, limit)
+		for id := from; id <= latest && int64(len(trades)) < limit; id++ {
+			trades = append(trades, fmt.Sprintf(
+				`{"a":%d,"p":"100.0","q":"0.5","f":%d,"l":%d,"T":%d,"m":%t,"M":true}`,
+				id, 2*id, 2*id+1, 1741735124000+id, id%2 == 0,
+			))
+		}
+		if _, err := w.Write([]byte("[" + strings.Join(trades, ",") + "]")); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestAggTrades(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle("/aggTrades", aggTradesHandler(t, 5000, &requests))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.AggTrades = true
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.SetState(state{LastAggTradeID: map[string]int64{"BTCEUR": 2499}}))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 3, requests)
+
+	var trades []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_agg_trade" {
+			trades = append(trades, m)
+		}
+	}
+	require.Len(t, trades, 2501)
+
+	expected := metric.New(
+		"binance_agg_trade",
+		map[string]string{"base": "BTC", "quote": "EUR", "side": "sell"},
+		map[string]interface{}{
+			"id":             int64(2500),
+			"price":          100.0,
+			"quantity":       0.5,
+			"quote_quantity": 50.0,
+			"first_trade_id": int64(5000),
+			"last_trade_id":  int64(5001),
+		},
+		time.UnixMilli(1741735124000+2500),
+	)
+	testutil.RequireMetricEqual(t, expected, trades[0])
+	require.Equal(t, "buy", trades[1].Tags()["side"])
+
+	// The next cycle must continue after the last reported aggregate trade
+	s, ok := plugin.GetState().(state)
+	require.True(t, ok)
+	require.Equal(t, int64(5000), s.LastAggTradeID["BTCEUR"])
+
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 4, requests)
+	require.Len(t, acc.GetTelegrafMetrics(), 1)
+}
+
+func TestAggTradesSummary(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle("/aggTrades", aggTradesHandler(t, 5000, &requests))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.AggTrades = true
+	plugin.AggTradesSummary = true
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.SetState(state{LastAggTradeID: map[string]int64{"BTCEUR": 4990}}))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_agg_trades",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{
+				"trade_count":        10,
+				"trades":             int64(20),
+				"buy_volume":         2.5,
+				"sell_volume":        2.5,
+				"min_price":          100.0,
+				"max_price":          100.0,
+				"vwap":               100.0,
+				"first_agg_trade_id": int64(4991),
+				"last_agg_trade_id":  int64(5000),
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_agg_trades" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), cmpopts.EquateApprox(0, 1e-9))
+
+	// Without new aggregate trades only the count is reported
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_agg_trades" {
+			require.Equal(t, map[string]interface{}{"trade_count": int64(0), "trades": int64(0)}, m.Fields())
+		}
+	}
+}
+
+func TestDepth(t *testing.T) {
+	tests := []struct {
+		name     string
+		layout   string
+		expected []telegraf.Metric
+	}{
+		{
+			name:   "aggregate",
+			layout: "aggregate",
+			expected: []telegraf.Metric{
+				metric.New(
+					"binance_depth",
+					map[string]string{"base": "BTC", "quote": "EUR"},
+					map[string]interface{}{
+						"last_update_id":            int64(1027024),
+						"bid_price_0":               76543.2,
+						"bid_quantity_0":            0.5,
+						"bid_cumulative_quantity_0": 0.5,
+						"bid_price_1":               76543.1,
+						"bid_quantity_1":            1.2,
+						"bid_cumulative_quantity_1": 1.7,
+						"bid_price_2":               76540.0,
+						"bid_quantity_2":            2.0,
+						"bid_cumulative_quantity_2": 3.7,
+						"ask_price_0":               76543.3,
+						"ask_quantity_0":            0.3,
+						"ask_cumulative_quantity_0": 0.3,
+						"ask_price_1":               76543.5,
+						"ask_quantity_1":            0.8,
+						"ask_cumulative_quantity_1": 1.1,
+						"ask_price_2":               76546.0,
+						"ask_quantity_2":            3.0,
+						"ask_cumulative_quantity_2": 4.1,
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name:   "per level",
+			layout: "per_level",
+			expected: []telegraf.Metric{
+				metric.New(
+					"binance_depth",
+					map[string]string{"base": "BTC", "quote": "EUR", "side": "ask", "level": "0"},
+					map[string]interface{}{"price": 76543.3, "quantity": 0.3, "cumulative_quantity": 0.3},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"binance_depth",
+					map[string]string{"base": "BTC", "quote": "EUR", "side": "ask", "level": "1"},
+					map[string]interface{}{"price": 76543.5, "quantity": 0.8, "cumulative_quantity": 1.1},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"binance_depth",
+					map[string]string{"base": "BTC", "quote": "EUR", "side": "ask", "level": "2"},
+					map[string]interface{}{"price": 76546.0, "quantity": 3.0, "cumulative_quantity": 4.1},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"binance_depth",
+					map[string]string{"base": "BTC", "quote": "EUR", "side": "bid", "level": "0"},
+					map[string]interface{}{"price": 76543.2, "quantity": 0.5, "cumulative_quantity": 0.5},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"binance_depth",
+					map[string]string{"base": "BTC", "quote": "EUR", "side": "bid", "level": "1"},
+					map[string]interface{}{"price": 76543.1, "quantity": 1.2, "cumulative_quantity": 1.7},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"binance_depth",
+					map[string]string{"base": "BTC", "quote": "EUR", "side": "bid", "level": "2"},
+					map[string]interface{}{"price": 76540.0, "quantity": 2.0, "cumulative_quantity": 3.7},
+					time.Unix(0, 0),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			defer server.Close()
+
+			plugin := newTestPlugin(server.URL)
+			plugin.Depth = true
+			plugin.DepthLimit = 10
+			plugin.DepthLayout = tt.layout
+			require.NoError(t, plugin.Init())
+
+			var acc testutil.Accumulator
+			require.NoError(t, plugin.Gather(&acc))
+			require.Empty(t, acc.Errors)
+
+			var actual []telegraf.Metric
+			for _, m := range acc.GetTelegrafMetrics() {
+				if m.Name() == "binance_depth" {
+					actual = append(actual, m)
+				}
+			}
+			testutil.RequireMetricsEqual(t, tt.expected, actual,
+				testutil.IgnoreTime(), testutil.SortMetrics(), cmpopts.EquateApprox(0, 1e-9))
+		})
+	}
+}
+
+func TestInitInvalidDepth(t *testing.T) {
+	plugin := newTestPlugin("http://localhost")
+	plugin.Depth = true
+	require.ErrorContains(t, plugin.Init(), "depth_limit 0 not between 1 and 5000")
+
+	plugin.DepthLimit = 10
+	plugin.DepthLayout = "wide"
+	require.ErrorContains(t, plugin.Init(), `invalid depth_layout "wide"`)
+
+	plugin.DepthLayout = ""
+	plugin.SlippageOrderSizes = []float64{1, -1}
+	require.ErrorContains(t, plugin.Init(), "slippage order size -1 must be positive")
+}
+
+func TestBookStats(t *testing.T) {
+	bids := []bookLevel{{100, 1}, {99.95, 2}, {99, 3}}
+	asks := []bookLevel{{100.1, 1}, {100.2, 2}, {101, 5}}
+
+	plugin := newTestPlugin("http://localhost")
+	plugin.DepthBps = []int{10, 50, 200}
+	plugin.DepthPressureLevels = 2
+	p := newPair(symbolInfo{Symbol: "BTCEUR", BaseAsset: "BTC", QuoteAsset: "EUR"})
+
+	var acc testutil.Accumulator
+	plugin.addBookStats(&acc, p, bids, asks, time.Unix(0, 0))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_book",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{
+				"best_bid":            100.0,
+				"best_ask":            100.1,
+				"mid":                 100.05,
+				"spread":              0.1,
+				"spread_bps":          9.995002498750626,
+				"microprice":          100.05,
+				"pressure":            0.5,
+				"imbalance":           0.0,
+				"imbalance_10bps":     (299.9 - 100.1) / 400.0,
+				"imbalance_50bps":     (299.9 - 300.5) / 600.4,
+				"imbalance_200bps":    (596.9 - 805.5) / 1402.4,
+				"bid_notional_10bps":  299.9,
+				"ask_notional_10bps":  100.1,
+				"bid_notional_50bps":  299.9,
+				"ask_notional_50bps":  300.5,
+				"bid_notional_200bps": 596.9,
+				"ask_notional_200bps": 805.5,
+				"bid_value":           596.9,
+				"ask_value":           805.5,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), cmpopts.EquateApprox(0, 1e-9))
+
+	plugin.addBookStats(&acc, p, bids, nil, time.Unix(0, 0))
+	require.Len(t, acc.Errors, 1)
+}
+
+func TestSlippage(t *testing.T) {
+	bids := []bookLevel{{100, 1}, {99.95, 2}, {99, 3}}
+	asks := []bookLevel{{100.1, 1}, {100.2, 2}, {101, 5}}
+
+	plugin := newTestPlugin("http://localhost")
+	plugin.SlippageOrderSizes = []float64{2, 10}
+	p := newPair(symbolInfo{Symbol: "BTCEUR", BaseAsset: "BTC", QuoteAsset: "EUR"})
+
+	var acc testutil.Accumulator
+	plugin.addSlippage(&acc, p, bids, asks, time.Unix(0, 0))
+	require.Empty(t, acc.Errors)
+
+	mid := 100.05
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_slippage",
+			map[string]string{"base": "BTC", "quote": "EUR", "side": "buy", "order_size": "2"},
+			map[string]interface{}{
+				"filled_quantity":  2.0,
+				"complete":         true,
+				"avg_price":        100.15,
+				"worst_price":      100.2,
+				"slippage_percent": (100.15 - mid) / mid * 100,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_slippage",
+			map[string]string{"base": "BTC", "quote": "EUR", "side": "sell", "order_size": "2"},
+			map[string]interface{}{
+				"filled_quantity":  2.0,
+				"complete":         true,
+				"avg_price":        99.975,
+				"worst_price":      99.95,
+				"slippage_percent": (mid - 99.975) / mid * 100,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_slippage",
+			map[string]string{"base": "BTC", "quote": "EUR", "side": "buy", "order_size": "10"},
+			map[string]interface{}{
+				"filled_quantity":  8.0,
+				"complete":         false,
+				"avg_price":        805.5 / 8,
+				"worst_price":      101.0,
+				"slippage_percent": (805.5/8 - mid) / mid * 100,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_slippage",
+			map[string]string{"base": "BTC", "quote": "EUR", "side": "sell", "order_size": "10"},
+			map[string]interface{}{
+				"filled_quantity":  6.0,
+				"complete":         false,
+				"avg_price":        596.9 / 6,
+				"worst_price":      99.0,
+				"slippage_percent": (mid - 596.9/6) / mid * 100,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), cmpopts.EquateApprox(0, 1e-9))
+}
+
+func TestPriceBands(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.PriceBands = true
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	price := 76543.21
+	expected := make([]telegraf.Metric, 0, 2)
+	for _, side := range []string{"bid", "ask"} {
+		expected = append(expected, metric.New(
+			"binance_price_band",
+			map[string]string{"base": "BTC", "quote": "EUR", "side": side},
+			map[string]interface{}{
+				"avg_price":              76500.0,
+				"avg_price_mins":         int64(5),
+				"lower":                  15300.0,
+				"upper":                  382500.0,
+				"lower_distance_percent": (price - 15300.0) / price * 100,
+				"upper_distance_percent": (382500.0 - price) / price * 100,
+			},
+			time.Unix(0, 0),
+		))
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_price_band" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), cmpopts.EquateApprox(0, 1e-9))
+}
+
+func TestAvgPrice(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.AvgPrice = true
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{
+				"price":          76543.21,
+				"avg_price":      76500.0,
+				"avg_price_mins": int64(5),
+			},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.IgnoreTime())
+}
+
+func TestSystemStatus(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.SystemStatus = true
+	plugin.sapiURL = server.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_status",
+			map[string]string{},
+			map[string]interface{}{
+				"status":      int64(1),
+				"message":     "system maintenance",
+				"maintenance": true,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_status" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestRateLimitUsage(t *testing.T) {
+	handler := testdataHandler(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-MBX-USED-WEIGHT-1M", "27")
+		handler(w, r)
+	}))
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.RateLimitUsage = true
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_rate_limit",
+			map[string]string{"host": host},
+			map[string]interface{}{"used_weight_1m": int64(27)},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_rate_limit" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+	require.Empty(t, plugin.usedWeights)
+}
+
+func TestBackOff(t *testing.T) {
+	var requests atomic.Int32
+	testdata := testdataHandler(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != priceEndpoint {
+			testdata(w, r)
+			return
+		}
+		requests.Add(1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTeapot)
+		if _, err := w.Write([]byte(`{"code":-1003,"msg":"Way too many requests; IP banned."}`)); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, acc.Errors, 1)
+	require.EqualValues(t, 1, requests.Load())
+
+	var backOffs []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_back_off" {
+			backOffs = append(backOffs, m)
+		}
+	}
+	require.Len(t, backOffs, 1)
+	require.Empty(t, backOffs[0].Tags())
+	fields := backOffs[0].Fields()
+	require.InDelta(t, 120, fields["remaining"], 5)
+	require.Contains(t, fields, "blocked_until")
+	delete(fields, "remaining")
+	delete(fields, "blocked_until")
+	require.Equal(t, map[string]interface{}{"status_code": int64(http.StatusTeapot), "banned": true}, fields)
+
+	// Subsequent gather cycles must not send any request until the back-off
+	// ended but report the back-off
+	acc.ClearMetrics()
+	acc.Errors = nil
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.EqualValues(t, 1, requests.Load())
+	metrics := acc.GetTelegrafMetrics()
+	require.Len(t, metrics, 1)
+	require.Equal(t, "binance_back_off", metrics[0].Name())
+
+	// Requests must resume after the back-off
+	plugin.budget.blockedUntil = time.Now()
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.EqualValues(t, 2, requests.Load())
+}
+
+func TestHTTPTiming(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	plugin := newTestPlugin(server.URL)
+	plugin.HTTPTiming = true
+	require.NoError(t, plugin.Init())
+
+	// The timing of the requests during initialization is emitted with the
+	// first gather cycle
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	var timings []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_http_timing" {
+			timings = append(timings, m)
+		}
+	}
+	require.Len(t, timings, 2)
+	for i, endpoint := range []string{"/exchangeInfo", "/ticker/price"} {
+		require.Equal(t, map[string]string{"host": host, "endpoint": endpoint, "status_code": "200"}, timings[i].Tags())
+		require.Contains(t, timings[i].Fields(), "response_time")
+		require.Contains(t, timings[i].Fields(), "first_byte_time")
+	}
+	require.Contains(t, timings[0].Fields(), "connect_time")
+	reused, ok := timings[1].Fields()["reused"].(bool)
+	require.True(t, ok)
+	require.True(t, reused)
+
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, acc.GetTelegrafMetrics(), 2)
+}
+
+// spanKeeper keeps the exported spans on shutdown for inspection
+type spanKeeper struct {
+	*tracetest.InMemoryExporter
+}
+
+func (*spanKeeper) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestTracing(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	exporter := &spanKeeper{tracetest.NewInMemoryExporter()}
+	plugin := newTestPlugin(server.URL)
+	plugin.spanExporter = exporter
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	// Pending spans are exported when stopping the plugin, including the ones
+	// of the requests during initialization without a parent
+	plugin.Stop()
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	initialization, request, gather := spans[0], spans[1], spans[2]
+	require.Equal(t, "GET /exchangeInfo", initialization.Name)
+	require.False(t, initialization.Parent.IsValid())
+	require.Equal(t, "gather", gather.Name)
+	require.Equal(t, "GET /ticker/price", request.Name)
+	require.Equal(t, gather.SpanContext.SpanID(), request.Parent.SpanID())
+	require.Equal(t, gather.SpanContext.TraceID(), request.SpanContext.TraceID())
+}
+
+func TestStop(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(signedHandler(t, "key", "secret"))
+	defer sapi.Close()
+	filename := filepath.Join(t.TempDir(), "audit.log")
+
+	exporter := &spanKeeper{tracetest.NewInMemoryExporter()}
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.AccountInfo = true
+	plugin.AuditLog = filename
+	plugin.AuditKey = config.NewSecret([]byte("audit-key"))
+	plugin.sapiURL = sapi.URL
+	plugin.spanExporter = exporter
+	require.NoError(t, plugin.Init())
+
+	// Without liquidations no stream is subscribed but the resources of the
+	// plugin must still be released
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Empty(t, exporter.GetSpans())
+	plugin.Stop()
+
+	require.NotEmpty(t, exporter.GetSpans())
+	require.Nil(t, plugin.tracerProvider)
+	require.Nil(t, plugin.auditFile)
+	buf, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Contains(t, string(buf), "/account/info")
+
+	// Stopping again must not fail
+	plugin.Stop()
+}
+
+func TestSocks5Proxy(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	var proxied atomic.Int32
+	proxy, err := socks5.New(&socks5.Config{
+		Dial: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			proxied.Add(1)
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	})
+	require.NoError(t, err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		// Serving ends with an error when closing the listener
+		_ = proxy.Serve(listener)
+	}()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.Socks5ProxyEnabled = true
+	plugin.Socks5ProxyAddress = listener.Addr().String()
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Len(t, acc.GetTelegrafMetrics(), 1)
+	require.Positive(t, proxied.Load())
+}
+
+func TestLocalAddress(t *testing.T) {
+	var remote string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remote = r.RemoteAddr
+		testdataHandler(t)(w, r)
+	}))
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.LocalAddress = "127.0.0.1"
+	require.NoError(t, plugin.Init())
+	host, _, err := net.SplitHostPort(remote)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1", host)
+
+	plugin = newTestPlugin(server.URL)
+	plugin.LocalAddress = "foo0"
+	require.ErrorContains(t, plugin.Init(), `local_address "foo0" is neither an IP address nor a network interface`)
+}
+
+func TestIPVersion(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.IPVersion = "ipv4"
+	require.NoError(t, plugin.Init())
+
+	// The test server only listens on IPv4
+	plugin = newTestPlugin(server.URL)
+	plugin.IPVersion = "ipv6"
+	require.Error(t, plugin.Init())
+
+	plugin = newTestPlugin(server.URL)
+	plugin.IPVersion = "ipv6"
+	plugin.LocalAddress = "127.0.0.1"
+	require.ErrorContains(t, plugin.Init(), `local_address "127.0.0.1" does not match the IP version`)
+
+	plugin = newTestPlugin(server.URL)
+	plugin.IPVersion = "ipv5"
+	require.ErrorContains(t, plugin.Init(), `invalid ip_version "ipv5"`)
+}
+
+func TestDNSCache(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+
+	plugin := newTestPlugin("http://localhost:" + port)
+	plugin.DNSCacheTTL = config.Duration(time.Hour)
+	require.NoError(t, plugin.Init())
+
+	// Count the lookups and disable keep-alive to dial on every request
+	var lookups int
+	plugin.dnsCache.entries = make(map[string]dnsEntry)
+	plugin.dnsCache.lookup = func(context.Context, string) ([]net.IPAddr, error) {
+		lookups++
+		return []net.IPAddr{{IP: net.IPv4(127, 0, 0, 1)}}, nil
+	}
+	transport, ok := plugin.client.Transport.(*http.Transport)
+	require.True(t, ok)
+	transport.DisableKeepAlives = true
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 1, lookups)
+
+	// Expired entries are resolved again
+	plugin.dnsCache.ttl = 0
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 2, lookups)
+
+	// Failing lookups fall back to the expired entry
+	plugin.dnsCache.lookup = func(context.Context, string) ([]net.IPAddr, error) {
+		return nil, errors.New("no such host")
+	}
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Len(t, acc.GetTelegrafMetrics(), 4)
+}
+
+func TestForceHTTP1(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+	serverTransport, ok := server.Client().Transport.(*http.Transport)
+	require.True(t, ok)
+
+	for _, tt := range []struct {
+		force    bool
+		expected string
+	}{{false, "HTTP/2.0"}, {true, "HTTP/1.1"}} {
+		plugin := newTestPlugin(server.URL)
+		plugin.ForceHTTP1 = tt.force
+		transport, err := plugin.newTransport()
+		require.NoError(t, err)
+		transport.TLSClientConfig = serverTransport.TLSClientConfig.Clone()
+
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		require.NoError(t, err)
+		buf, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, string(buf))
+	}
+}
+
+func TestErrorCategory(t *testing.T) {
+	require.Equal(t, "rate_limit", errorCategory(-1003))
+	require.Equal(t, "invalid_symbol", errorCategory(-1121))
+	require.Equal(t, "invalid_api_key", errorCategory(-2015))
+	require.Equal(t, "server", errorCategory(-1099))
+	require.Equal(t, "invalid_request", errorCategory(-1199))
+	require.Equal(t, "rejected", errorCategory(-2011))
+	require.Equal(t, "unknown", errorCategory(-9000))
+
+	err := &apiError{Code: -1003, Msg: "Too many requests."}
+	require.EqualError(t, err, "Too many requests. (code -1003, rate_limit)")
+}
+
+func TestErrorCategoryLogged(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"code":-2015,"msg":"Invalid API-key, IP, or permissions for action."}`))
+	}))
+	defer sapi.Close()
+
+	logger := &testutil.CaptureLogger{Name: "inputs.binance"}
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.AccountInfo = true
+	plugin.sapiURL = sapi.URL
+	plugin.Log = logger
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.NotEmpty(t, acc.Errors)
+
+	var entries []testutil.Entry
+	for _, e := range logger.Messages() {
+		if e.Level == testutil.LevelWarn {
+			entries = append(entries, e)
+		}
+	}
+	require.Len(t, entries, 1)
+	require.Contains(t, entries[0].Text, "Invalid API-key, IP, or permissions for action. (code -2015, invalid_api_key)")
+	require.Equal(t, "invalid_api_key", entries[0].Attributes["error_category"])
+}
+
+func TestProbeEndpoints(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	ping := func(delay time.Duration) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/ping" {
+				w.WriteHeader(http.StatusNotFound)
+				t.Errorf("unexpected path %q", r.URL.Path)
+				return
+			}
+			time.Sleep(delay)
+			_, _ = w.Write([]byte("{}"))
+		}))
+	}
+	slow, fast, down := ping(50*time.Millisecond), ping(0), ping(0)
+	defer slow.Close()
+	defer fast.Close()
+	down.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.ProbeEndpoints = true
+	plugin.ProbeInterval = config.Duration(time.Hour)
+	plugin.probeURLs = map[string]string{
+		"api.binance.com":  slow.URL,
+		"api1.binance.com": fast.URL,
+		"api2.binance.com": down.URL,
+	}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	var probes []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_endpoint_probe" {
+			probes = append(probes, m)
+		}
+	}
+	require.Len(t, probes, 3)
+	for i, host := range []string{"api1.binance.com", "api.binance.com"} {
+		require.Equal(t, host, probes[i].Tags()["host"])
+		require.Equal(t, int64(i+1), probes[i].Fields()["rank"])
+		reachable, ok := probes[i].Fields()["reachable"].(bool)
+		require.True(t, ok && reachable)
+		require.Contains(t, probes[i].Fields(), "latency")
+	}
+	require.Equal(t, map[string]interface{}{"reachable": false}, probes[2].Fields())
+
+	// Probes are only repeated after the interval
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, acc.GetTelegrafMetrics(), 1)
+}
+
+// signedHandler verifies the API key and the signature of requests before
+// serving the test data
+func signedHandler(t *testing.T, key, secret string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query, signature, found := strings.Cut(r.URL.RawQuery, "&signature=")
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(query))
+		if !found || signature != hex.EncodeToString(mac.Sum(nil)) || r.Header.Get("X-MBX-APIKEY") != key {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":-1022,"msg":"Signature for this request is not valid."}`))
+			t.Errorf("invalid signature or API key for %q", r.URL.String())
+			return
+		}
+		if !r.URL.Query().Has("timestamp") {
+			w.WriteHeader(http.StatusBadRequest)
+			t.Error("missing timestamp")
+			return
+		}
+		testdataHandler(t)(w, r)
+	}
+}
+
+func TestSignatureMethods(t *testing.T) {
+	ed25519Public, ed25519Private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		method string
+		key    crypto.Signer
+		verify func(query string, signature []byte) error
+	}{
+		{
+			method: "ed25519",
+			key:    ed25519Private,
+			verify: func(query string, signature []byte) error {
+				if !ed25519.Verify(ed25519Public, []byte(query), signature) {
+					return errors.New("invalid signature")
+				}
+				return nil
+			},
+		},
+		{
+			method: "rsa",
+			key:    rsaPrivate,
+			verify: func(query string, signature []byte) error {
+				digest := sha256.Sum256([]byte(query))
+				return rsa.VerifyPKCS1v15(&rsaPrivate.PublicKey, crypto.SHA256, digest[:], signature)
+			},
+		},
+	}
+
+	server := newTestServer(t)
+	defer server.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			der, err := x509.MarshalPKCS8PrivateKey(tt.key)
+			require.NoError(t, err)
+			filename := filepath.Join(t.TempDir(), "key.pem")
+			require.NoError(t, os.WriteFile(filename, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+			plugin := newTestPlugin(server.URL)
+			plugin.APIKey = config.NewSecret([]byte("key"))
+			plugin.SignatureMethod = tt.method
+			plugin.PrivateKeyFile = filename
+			require.NoError(t, plugin.Init())
+
+			query, err := plugin.signedQuery(url.Values{"asset": {"BTC"}}, time.Now())
+			require.NoError(t, err)
+			query, encoded, found := strings.Cut(query, "&signature=")
+			require.True(t, found)
+			encoded, err = url.QueryUnescape(encoded)
+			require.NoError(t, err)
+			signature, err := base64.StdEncoding.DecodeString(encoded)
+			require.NoError(t, err)
+			require.NoError(t, tt.verify(query, signature))
+
+			// The key must match the signature method
+			plugin = newTestPlugin(server.URL)
+			plugin.SignatureMethod = map[string]string{"ed25519": "rsa", "rsa": "ed25519"}[tt.method]
+			plugin.PrivateKeyFile = filename
+			require.ErrorContains(t, plugin.Init(), "does not match signature method")
+		})
+	}
+}
+
+func TestInitInvalidSignatureMethod(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.SignatureMethod = "ecdsa"
+	require.ErrorContains(t, plugin.Init(), "invalid signature_method \"ecdsa\"")
+
+	plugin = newTestPlugin("")
+	plugin.SignatureMethod = "ed25519"
+	require.ErrorContains(t, plugin.Init(), "signature method \"ed25519\" requires private_key_file to be set")
+}
+
+func TestServerTimeOffset(t *testing.T) {
+	// The server clock is ahead of the local one by a minute
+	offset := time.Minute
+	testdata := testdataHandler(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != serverTimeEndpoint {
+			testdata(w, r)
+			return
+		}
+		if _, err := fmt.Fprintf(w, `{"serverTime":%d}`, time.Now().Add(offset).UnixMilli()); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	var timestamps []int64
+	var recvWindow string
+	sapi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timestamp, err := strconv.ParseInt(r.URL.Query().Get("timestamp"), 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			t.Error(err)
+			return
+		}
+		timestamps = append(timestamps, timestamp)
+		recvWindow = r.URL.Query().Get("recvWindow")
+		// Reject the second request as if the clock drifted
+		if len(timestamps) == 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":-1021,"msg":"Timestamp for this request was 1000ms ahead of the server's time."}`))
+			return
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.RecvWindow = config.Duration(10 * time.Second)
+	plugin.ServerTimeRefresh = config.Duration(time.Hour)
+	require.NoError(t, plugin.Init())
+
+	var v map[string]interface{}
+	require.NoError(t, plugin.querySigned(t.Context(), sapi.URL, url.Values{}, 1, &v))
+	require.Len(t, timestamps, 1)
+	require.InDelta(t, time.Now().Add(offset).UnixMilli(), timestamps[0], 1000)
+	require.Equal(t, "10000", recvWindow)
+
+	// An invalid timestamp must trigger a resynchronization
+	require.ErrorContains(t, plugin.querySigned(t.Context(), sapi.URL, url.Values{}, 1, &v), "Timestamp for this request")
+	require.True(t, plugin.serverTimeSynced.IsZero())
+}
+
+func TestInitInvalidRecvWindow(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.RecvWindow = config.Duration(2 * time.Minute)
+	require.ErrorContains(t, plugin.Init(), "recv_window must be positive and at most one minute")
+}
+
+func TestAccountBalances(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(accountEndpoint, signedHandler(t, "key", "secret"))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.AccountBalances = true
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_balance",
+			map[string]string{"asset": "BTC"},
+			map[string]interface{}{"free": 0.512, "locked": 0.1, "total": 0.612},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_balance",
+			map[string]string{"asset": "EUR"},
+			map[string]interface{}{"free": 1250.0, "locked": 0.0, "total": 1250.0},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_balance",
+			map[string]string{"asset": "USDT"},
+			map[string]interface{}{"free": 3000.0, "locked": 500.0, "total": 3500.0},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_balance" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), cmpopts.EquateApprox(0, 1e-9))
+}
+
+func TestPortfolioValuation(t *testing.T) {
+	testdata := testdataHandler(t)
+	mux := http.NewServeMux()
+	mux.Handle(accountEndpoint, signedHandler(t, "key", "secret"))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		// BTC is only valued in EUR via the USDT pairs
+		if r.URL.Path == priceEndpoint && r.URL.RawQuery == "" {
+			if _, err := w.Write([]byte(`[{"symbol":"BTCUSDT","price":"82345.67"},{"symbol":"EURUSDT","price":"1.085"}]`)); err != nil {
+				t.Error(err)
+			}
+			return
+		}
+		testdata(w, r)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.AccountBalances = true
+	plugin.PortfolioValuation = "eur"
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	btc := 0.612 * 82345.67 / 1.085
+	usdt := 3500 / 1.085
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_balance",
+			map[string]string{"asset": "BTC", "valuation_quote": "EUR"},
+			map[string]interface{}{"free": 0.512, "locked": 0.1, "total": 0.612, "value": btc},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_balance",
+			map[string]string{"asset": "EUR", "valuation_quote": "EUR"},
+			map[string]interface{}{"free": 1250.0, "locked": 0.0, "total": 1250.0, "value": 1250.0},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_balance",
+			map[string]string{"asset": "USDT", "valuation_quote": "EUR"},
+			map[string]interface{}{"free": 3000.0, "locked": 500.0, "total": 3500.0, "value": usdt},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_portfolio",
+			map[string]string{"valuation_quote": "EUR"},
+			map[string]interface{}{"value": btc + 1250 + usdt, "assets": 3, "unvalued_assets": 0},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_balance" || m.Name() == "binance_portfolio" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), cmpopts.EquateApprox(0, 1e-9))
+}
+
+func TestOpenOrders(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(openOrdersEndpoint, signedHandler(t, "key", "secret"))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.OpenOrders = true
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	// The ages depend on the current time, so check them separately
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_open_orders",
+			map[string]string{"base": "BTC", "quote": "EUR", "side": "buy"},
+			map[string]interface{}{"orders": 2, "remaining_quantity": 0.25, "notional": 18650.0},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_open_orders",
+			map[string]string{"base": "BTC", "quote": "EUR", "side": "sell"},
+			map[string]interface{}{"orders": 1, "remaining_quantity": 0.3, "notional": 21000.0},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() != "binance_open_orders" {
+			continue
+		}
+		oldest, found := m.GetField("oldest_age")
+		require.True(t, found)
+		mean, found := m.GetField("mean_age")
+		require.True(t, found)
+		if side, _ := m.GetTag("side"); side == "buy" {
+			require.InDelta(t, time.Since(time.UnixMilli(1741730000000)).Seconds(), oldest, 5)
+			require.InDelta(t, time.Since(time.UnixMilli(1741732000000)).Seconds(), mean, 5)
+		} else {
+			require.InDelta(t, time.Since(time.UnixMilli(1741732000000)).Seconds(), oldest, 5)
+			require.InDelta(t, time.Since(time.UnixMilli(1741732000000)).Seconds(), mean, 5)
+		}
+		m.RemoveField("oldest_age")
+		m.RemoveField("mean_age")
+		actual = append(actual, m)
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), cmpopts.EquateApprox(0, 1e-9))
+}
+
+func TestMyTrades(t *testing.T) {
+	var fromID string
+	mux := http.NewServeMux()
+	mux.HandleFunc(myTradesEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		fromID = r.URL.Query().Get("fromId")
+		signedHandler(t, "key", "secret")(w, r)
+	})
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.MyTrades = true
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Empty(t, fromID)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_fill",
+			map[string]string{"base": "BTC", "quote": "EUR", "side": "buy", "role": "maker"},
+			map[string]interface{}{
+				"id":               int64(501),
+				"order_id":         int64(28457),
+				"price":            75000.0,
+				"quantity":         0.05,
+				"quote_quantity":   3750.0,
+				"commission":       0.00005,
+				"commission_asset": "BTC",
+			},
+			time.UnixMilli(1741731000000),
+		),
+		metric.New(
+			"binance_fill",
+			map[string]string{"base": "BTC", "quote": "EUR", "side": "sell", "role": "taker"},
+			map[string]interface{}{
+				"id":               int64(502),
+				"order_id":         int64(28460),
+				"price":            76000.0,
+				"quantity":         0.1,
+				"quote_quantity":   7600.0,
+				"commission":       0.0015,
+				"commission_asset": "BNB",
+			},
+			time.UnixMilli(1741733000000),
+		),
+		metric.New(
+			"binance_fill",
+			map[string]string{"base": "BTC", "quote": "EUR", "side": "sell", "role": "maker"},
+			map[string]interface{}{
+				"id":               int64(503),
+				"order_id":         int64(28461),
+				"price":            76500.0,
+				"quantity":         0.02,
+				"quote_quantity":   1530.0,
+				"commission":       1.53,
+				"commission_asset": "EUR",
+			},
+			time.UnixMilli(1741734000000),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_fill" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+
+	// The next cycle must continue after the last reported fill
+	s, ok := plugin.GetState().(state)
+	require.True(t, ok)
+	require.Equal(t, int64(503), s.LastMyTradeID["BTCEUR"])
+
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, "504", fromID)
+}
+
+func TestMyTradesSummary(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(myTradesEndpoint, signedHandler(t, "key", "secret"))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.MyTrades = true
+	plugin.MyTradesSummary = true
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_fills",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{
+				"fills":               3,
+				"maker_fills":         2,
+				"buy_quantity":        0.05,
+				"buy_quote_quantity":  3750.0,
+				"buy_avg_price":       75000.0,
+				"sell_quantity":       0.12,
+				"sell_quote_quantity": 9130.0,
+				"sell_avg_price":      9130.0 / 0.12,
+				"commission_btc":      0.00005,
+				"commission_bnb":      0.0015,
+				"commission_eur":      1.53,
+				"first_fill_id":       int64(501),
+				"last_fill_id":        int64(503),
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_fills" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), cmpopts.EquateApprox(0, 1e-9))
+}
+
+func TestTradeFees(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(signedHandler(t, "key", "secret"))
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.TradeFees = true
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_trade_fee",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{"maker_commission": 0.00075, "taker_commission": 0.001},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_trade_fee" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestTransferHistory(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(signedHandler(t, "key", "secret"))
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.DepositHistory = true
+	plugin.WithdrawalHistory = true
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_deposit",
+			map[string]string{"coin": "BTC", "network": "BTC", "status": "success"},
+			map[string]interface{}{"id": "769800519366885376", "amount": 0.5, "tx_id": "b3c8f2a1d4e5"},
+			time.UnixMilli(1741730000000),
+		),
+		metric.New(
+			"binance_deposit",
+			map[string]string{"coin": "USDT", "network": "TRX", "status": "pending"},
+			map[string]interface{}{"id": "769800519366885377", "amount": 1000.0, "tx_id": "9f8e7d6c5b4a"},
+			time.UnixMilli(1741733000000),
+		),
+		metric.New(
+			"binance_transfers",
+			map[string]string{"type": "deposit"},
+			map[string]interface{}{"records": 2},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_withdrawal",
+			map[string]string{"coin": "BTC", "network": "BTC", "status": "completed"},
+			map[string]interface{}{
+				"id":              "b6ae22b3aa844210a7041aee7589627c",
+				"amount":          0.1,
+				"transaction_fee": 0.0002,
+				"tx_id":           "4e7c1f0b2a3d",
+			},
+			time.UnixMilli(1741731200000),
+		),
+		metric.New(
+			"binance_transfers",
+			map[string]string{"type": "withdrawal"},
+			map[string]interface{}{"records": 1},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		switch m.Name() {
+		case "binance_deposit", "binance_withdrawal":
+			actual = append(actual, m)
+		case "binance_transfers":
+			m.SetTime(time.Unix(0, 0))
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+
+	// The next cycle must continue after the last reported records
+	s, ok := plugin.GetState().(state)
+	require.True(t, ok)
+	require.Equal(t, map[string]int64{"deposit": 1741733000000, "withdrawal": 1741731200000}, s.LastTransfer)
+}
+
+// depositsHandler serves the deposits created since the start time of the
+// query, newest first, with paging by offset
+func depositsHandler(t *testing.T, deposits *[]deposit, requests *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		q := r.URL.Query()
+		var values [3]int64
+		for i, name := range []string{"startTime", "offset", "limit"} {
+			v, err := strconv.ParseInt(q.Get(name), 10, 64)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				t.Errorf("invalid %s: %v", name, err)
+				return
+			}
+			values[i] = v
+		}
+		start, offset, limit := values[0], values[1], values[2]
+
+		page := make([]deposit, 0, limit)
+		for i := len(*deposits) - 1; i >= 0; i-- {
+			if d := (*deposits)[i]; d.InsertTime >= start {
+				page = append(page, d)
+			}
+		}
+		page = page[min(offset, int64(len(page))):]
+		page = page[:min(limit, int64(len(page)))]
+		if err := json.NewEncoder(w).Encode(page); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestTransferHistoryPaging(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	// More deposits than returned by a single request
+	deposits := make([]deposit, 0, 1500)
+	created := time.Now().Add(-24 * time.Hour).UnixMilli()
+	for i := range 1500 {
+		deposits = append(deposits, deposit{
+			ID:         strconv.Itoa(i),
+			Amount:     "1.0",
+			Coin:       "BTC",
+			Network:    "BTC",
+			Status:     1,
+			InsertTime: created + int64(i),
+		})
+	}
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle(depositHistoryEndpoint, depositsHandler(t, &deposits, &requests))
+	sapi := httptest.NewServer(mux)
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.DepositHistory = true
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 2, requests)
+
+	ids := make(map[string]bool, len(deposits))
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_deposit" {
+			id, found := m.GetField("id")
+			require.True(t, found)
+			ids[id.(string)] = true
+		}
+	}
+	require.Len(t, ids, 1500)
+	s, ok := plugin.GetState().(state)
+	require.True(t, ok)
+	require.Equal(t, created+1499, s.LastTransfer["deposit"])
+
+	// No deposits are left for the next cycle
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 3, requests)
+	for _, m := range acc.GetTelegrafMetrics() {
+		require.NotEqual(t, "binance_deposit", m.Name())
+	}
+}
+
+func TestTransferHistoryPending(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	created := time.Now().Add(-time.Hour).UnixMilli()
+	deposits := []deposit{
+		{ID: "1", Amount: "0.5", Coin: "BTC", Network: "BTC", Status: 0, InsertTime: created},
+		{ID: "2", Amount: "1.5", Coin: "BTC", Network: "BTC", Status: 1, InsertTime: created + 1000},
+	}
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle(depositHistoryEndpoint, depositsHandler(t, &deposits, &requests))
+	sapi := httptest.NewServer(mux)
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.DepositHistory = true
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	statuses := func(acc *testutil.Accumulator) map[string]string {
+		result := make(map[string]string)
+		for _, m := range acc.GetTelegrafMetrics() {
+			if m.Name() == "binance_deposit" {
+				id, _ := m.GetField("id")
+				result[id.(string)] = m.Tags()["status"]
+			}
+		}
+		return result
+	}
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, map[string]string{"1": "pending", "2": "success"}, statuses(&acc))
+
+	// Unchanged pending deposits are not reported again
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Empty(t, statuses(&acc))
+
+	// The pending deposit is reported again with its final status, also
+	// after a restart
+	s, ok := plugin.GetState().(state)
+	require.True(t, ok)
+	require.Equal(t, map[string]pendingTransfer{"deposit/1": {Time: created, Status: 0}}, s.PendingTransfer)
+	plugin = newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.DepositHistory = true
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.SetState(s))
+
+	deposits[0].Status = 1
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, map[string]string{"1": "success"}, statuses(&acc))
+
+	s, ok = plugin.GetState().(state)
+	require.True(t, ok)
+	require.Empty(t, s.PendingTransfer)
+	require.Equal(t, created+1000, s.LastTransfer["deposit"])
+}
+
+func TestTransferHistoryParseError(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	created := time.Now().Add(-time.Hour).UnixMilli()
+	deposits := []deposit{
+		{ID: "1", Amount: "0.5", Coin: "BTC", Network: "BTC", Status: 1, InsertTime: created},
+		{ID: "2", Amount: "invalid", Coin: "BTC", Network: "BTC", Status: 1, InsertTime: created + 1000},
+	}
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle(depositHistoryEndpoint, depositsHandler(t, &deposits, &requests))
+	sapi := httptest.NewServer(mux)
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.DepositHistory = true
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	// Records are only reported if all of them can be parsed
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, acc.Errors, 1)
+	require.ErrorContains(t, acc.Errors[0], "parsing deposit 2 failed")
+	for _, m := range acc.GetTelegrafMetrics() {
+		require.NotEqual(t, "binance_deposit", m.Name())
+	}
+	require.NotContains(t, plugin.state.LastTransfer, "deposit")
+
+	// The whole window is retried without duplicates once fixed
+	deposits[1].Amount = "1.5"
+	acc.ClearMetrics()
+	acc.Errors = nil
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	var ids []string
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_deposit" {
+			id, _ := m.GetField("id")
+			ids = append(ids, id.(string))
+		}
+	}
+	require.ElementsMatch(t, []string{"1", "2"}, ids)
+	require.Equal(t, created+1000, plugin.state.LastTransfer["deposit"])
+}
+
+func TestMarginAccount(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(signedHandler(t, "key", "secret"))
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.MarginAccount = true
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	// Assets neither held nor borrowed are skipped
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_margin_account",
+			map[string]string{"mode": "cross"},
+			map[string]interface{}{
+				"margin_level":        2.5,
+				"total_asset_btc":     1.5,
+				"total_liability_btc": 0.6,
+				"total_net_asset_btc": 0.9,
+				"borrow_enabled":      true,
+				"trade_enabled":       true,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_margin_asset",
+			map[string]string{"mode": "cross", "asset": "BTC"},
+			map[string]interface{}{"free": 1.2, "locked": 0.1, "borrowed": 0.0, "interest": 0.0, "net_asset": 1.3},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_margin_asset",
+			map[string]string{"mode": "cross", "asset": "USDT"},
+			map[string]interface{}{"free": 15000.0, "locked": 0.0, "borrowed": 45000.0, "interest": 12.5, "net_asset": -30012.5},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_margin_account" || m.Name() == "binance_margin_asset" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestIsolatedMarginAccount(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(signedHandler(t, "key", "secret"))
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.IsolatedAccount = true
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_margin_account",
+			map[string]string{"mode": "isolated", "base": "BTC", "quote": "EUR"},
+			map[string]interface{}{
+				"enabled":             true,
+				"trade_enabled":       true,
+				"margin_level":        1.6,
+				"margin_level_status": "NORMAL",
+				"margin_ratio":        5.0,
+				"index_price":         76000.0,
+				"liquidation_price":   104615.38,
+				"liquidation_rate":    27.35,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_margin_asset",
+			map[string]string{"mode": "isolated", "base": "BTC", "quote": "EUR", "asset": "BTC"},
+			map[string]interface{}{"free": 0.05, "locked": 0.0, "borrowed": 0.1, "interest": 0.00001, "net_asset": -0.05001},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_margin_asset",
+			map[string]string{"mode": "isolated", "base": "BTC", "quote": "EUR", "asset": "EUR"},
+			map[string]interface{}{"free": 12000.0, "locked": 0.0, "borrowed": 0.0, "interest": 0.0, "net_asset": 12000.0},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_margin_account" || m.Name() == "binance_margin_asset" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestCryptoLoans(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	var collateralRequests int
+	sapi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == loanCollateralDataEndpoint {
+			collateralRequests++
+		}
+		signedHandler(t, "key", "secret")(w, r)
+	}))
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.CryptoLoans = true
+	plugin.sapiV2URL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_crypto_loan",
+			map[string]string{"loan_coin": "USDT", "collateral_coin": "BTC"},
+			map[string]interface{}{
+				"total_debt":        10000.0,
+				"collateral_amount": 0.25,
+				"collateral_value":  20000.0,
+				"current_ltv":       0.5,
+				"initial_ltv":       0.78,
+				"margin_call_ltv":   0.85,
+				"liquidation_ltv":   0.91,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_crypto_loan" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+
+	// The thresholds are not queried again within the refresh interval
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, 1, collateralRequests)
+}
+
+func TestSimpleEarn(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(signedHandler(t, "key", "secret"))
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.SimpleEarn = true
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_earn",
+			map[string]string{"type": "flexible", "asset": "USDT", "product": "USDT001"},
+			map[string]interface{}{
+				"principal":         1500.0,
+				"apr":               0.0425,
+				"accrued_rewards":   12.75,
+				"yesterday_rewards": 0.17465753,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_earn",
+			map[string]string{"type": "locked", "asset": "AXS", "product": "Axs*90", "position": "123123"},
+			map[string]interface{}{
+				"principal":       122.09202928,
+				"apr":             0.2032,
+				"accrued_rewards": 0.27187021,
+				"duration_days":   90.0,
+				"accrual_days":    4.0,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_earn" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestStaking(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(signedHandler(t, "key", "secret"))
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.Staking = []string{"eth", "SOL"}
+	plugin.sapiURL = sapi.URL
+	plugin.sapiV2URL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_staking",
+			map[string]string{"asset": "ETH", "token": "WBETH"},
+			map[string]interface{}{
+				"holding":            1.22330928,
+				"token_amount":       1.10928781,
+				"thirty_days_profit": 0.00330928,
+				"exchange_rate":      1.0645,
+				"apr":                0.0295,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_staking",
+			map[string]string{"asset": "SOL", "token": "BNSOL"},
+			map[string]interface{}{
+				"holding":            10.52,
+				"token_amount":       10.0,
+				"thirty_days_profit": 0.056,
+				"exchange_rate":      1.052,
+				"apr":                0.072,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_staking" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestInitInvalidStakingAsset(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.Staking = []string{"BNB"}
+	require.ErrorContains(t, plugin.Init(), `invalid staking asset "BNB"`)
+}
+
+func TestSubAccounts(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(signedHandler(t, "key", "secret"))
+	defer sapi.Close()
+	// The amounts are numbers or strings depending on the API version
+	assets := map[string]string{
+		"desk-a@example.com": `{"balances":[{"asset":"BTC","free":1.5,"locked":0.5},{"asset":"ETH","free":0,"locked":0}]}`,
+		"desk-b@example.com": `{"balances":[{"asset":"USDT","free":"2500.00000000","locked":"0.00000000"}]}`,
+	}
+	sapiV4 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, found := assets[r.URL.Query().Get("email")]
+		if r.URL.Path != subAccountAssetsEndpoint || !found {
+			w.WriteHeader(http.StatusNotFound)
+			t.Errorf("unexpected request %q", r.URL.String())
+			return
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer sapiV4.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.SubAccounts = true
+	plugin.sapiURL = sapi.URL
+	plugin.sapiV4URL = sapiV4.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_balance",
+			map[string]string{"asset": "BTC", "sub_account_email": "desk-a@example.com"},
+			map[string]interface{}{"free": 1.5, "locked": 0.5, "total": 2.0},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_balance",
+			map[string]string{"asset": "USDT", "sub_account_email": "desk-b@example.com"},
+			map[string]interface{}{"free": 2500.0, "locked": 0.0, "total": 2500.0},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_balance" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestKeyPermissions(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	var requests int
+	sapi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		signedHandler(t, "key", "secret")(w, r)
+	}))
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.KeyPermissions = true
+	plugin.ExpectedPermissions = []string{"reading"}
+	plugin.PermissionsRefresh = config.Duration(time.Hour)
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_api_key",
+			map[string]string{},
+			map[string]interface{}{
+				"ip_restrict":              false,
+				"create_time":              int64(1698645219000),
+				"reading":                  true,
+				"spot_and_margin_trading":  false,
+				"margin":                   false,
+				"futures":                  false,
+				"vanilla_options":          false,
+				"portfolio_margin_trading": false,
+				"withdrawals":              true,
+				"internal_transfer":        false,
+				"universal_transfer":       false,
+				"fix_api_trade":            false,
+				"fix_read_only":            false,
+				"unexpected_permissions":   1,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_api_key" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+
+	// The permissions are not queried again within the refresh interval
+	require.NoError(t, plugin.Gather(&acc))
+	require.Equal(t, 1, requests)
+}
+
+func TestInitInvalidExpectedPermission(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.ExpectedPermissions = []string{"trading"}
+	require.ErrorContains(t, plugin.Init(), `invalid expected permission "trading"`)
+}
+
+func TestConvertQuotes(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			t.Errorf("unexpected method %s", r.Method)
+			return
+		}
+		signedHandler(t, "key", "secret")(w, r)
+	}))
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.ConvertQuotes = []*convertQuote{{FromAsset: "usdt", ToAsset: "btc", Amount: 1000}}
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	// The spot price of USDT in BTC is the inverse of the BTCUSDT price
+	spot := 1 / 82345.67
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_convert_quote",
+			map[string]string{"from_asset": "USDT", "to_asset": "BTC"},
+			map[string]interface{}{
+				"amount":         1000.0,
+				"to_amount":      0.0121,
+				"ratio":          0.0000121,
+				"inverse_ratio":  82644.62809917,
+				"spot_ratio":     spot,
+				"spread_percent": (spot - 0.0000121) / spot * 100,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_convert_quote" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), cmpopts.EquateApprox(0, 1e-9))
+}
+
+func TestInitInvalidConvertQuote(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.ConvertQuotes = []*convertQuote{{FromAsset: "USDT", ToAsset: "BTC"}}
+	require.ErrorContains(t, plugin.Init(), "convert quote amount 0 must be positive")
+
+	plugin = newTestPlugin("")
+	plugin.ConvertQuotes = []*convertQuote{{FromAsset: "USDT", Amount: 100}}
+	require.ErrorContains(t, plugin.Init(), "convert quote from_asset and to_asset cannot be empty")
+
+	plugin = newTestPlugin("")
+	plugin.ConvertQuotes = []*convertQuote{{FromAsset: "USDT", ToAsset: "BTC", Amount: 100}}
+	require.ErrorContains(t, plugin.Init(), "convert quotes require api_key and api_secret or private_key_file to be set")
+}
+
+func TestCommissionRates(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(commissionEndpoint, signedHandler(t, "key", "secret"))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.CommissionRates = true
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_commission",
+			map[string]string{"base": "BTC", "quote": "EUR", "discount_asset": "BNB"},
+			map[string]interface{}{
+				"standard_maker":   0.001,
+				"standard_taker":   0.001,
+				"special_maker":    0.0,
+				"special_taker":    0.0,
+				"tax_maker":        0.0,
+				"tax_taker":        0.0001,
+				"discount":         0.75,
+				"discount_enabled": true,
+				"effective_maker":  0.00075,
+				"effective_taker":  0.00085,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_commission" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), cmpopts.EquateApprox(0, 1e-12))
+}
+
+func TestAccountInfo(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(signedHandler(t, "key", "secret"))
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.AccountInfo = true
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_account",
+			map[string]string{},
+			map[string]interface{}{
+				"vip_level":                1,
+				"margin_enabled":           true,
+				"futures_enabled":          false,
+				"options_enabled":          false,
+				"portfolio_margin_enabled": false,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_account" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestAuditLog(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(signedHandler(t, "key", "secret"))
+	defer sapi.Close()
+	filename := filepath.Join(t.TempDir(), "audit.log")
+
+	// A restarted plugin continues the chain of the existing log
+	for range 2 {
+		plugin := newTestPlugin(server.URL)
+		plugin.APIKey = config.NewSecret([]byte("key"))
+		plugin.APISecret = config.NewSecret([]byte("secret"))
+		plugin.AccountInfo = true
+		plugin.AuditLog = filename
+		plugin.AuditKey = config.NewSecret([]byte("audit-key"))
+		plugin.sapiURL = sapi.URL
+		require.NoError(t, plugin.Init())
+
+		var acc testutil.Accumulator
+		require.NoError(t, plugin.Gather(&acc))
+		require.Empty(t, acc.Errors)
+		plugin.Stop()
+	}
+
+	buf, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	require.Len(t, lines, 2)
+
+	var prev string
+	for _, line := range lines {
+		require.NotContains(t, line, "signature")
+		require.NotContains(t, line, "secret")
+
+		var record auditRecord
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		require.Equal(t, "GET", record.Method)
+		require.Equal(t, "/account/info", record.Endpoint)
+		require.Equal(t, 200, record.StatusCode)
+		require.Contains(t, record.Params, "timestamp")
+		require.Equal(t, prev, record.PrevHash)
+		prev = record.Hash
+	}
+	hash, err := verifyAuditLog(bytes.NewReader(buf), []byte("audit-key"))
+	require.NoError(t, err)
+	require.Equal(t, prev, hash)
+}
+
+func TestAuditLogTampered(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(signedHandler(t, "key", "secret"))
+	defer sapi.Close()
+	filename := filepath.Join(t.TempDir(), "audit.log")
+
+	newPlugin := func() *Binance {
+		plugin := newTestPlugin(server.URL)
+		plugin.APIKey = config.NewSecret([]byte("key"))
+		plugin.APISecret = config.NewSecret([]byte("secret"))
+		plugin.AccountInfo = true
+		plugin.AuditLog = filename
+		plugin.AuditKey = config.NewSecret([]byte("audit-key"))
+		plugin.sapiURL = sapi.URL
+		return plugin
+	}
+	for range 2 {
+		plugin := newPlugin()
+		require.NoError(t, plugin.Init())
+		var acc testutil.Accumulator
+		require.NoError(t, plugin.Gather(&acc))
+		require.Empty(t, acc.Errors)
+		plugin.Stop()
+	}
+	buf, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	_, err = verifyAuditLog(bytes.NewReader(buf), []byte("audit-key"))
+	require.NoError(t, err)
+
+	// Without the key the hashes cannot be recomputed
+	_, err = verifyAuditLog(bytes.NewReader(buf), []byte("other-key"))
+	require.ErrorContains(t, err, "record in line 1 has been modified")
+
+	// Modifying a record breaks the chain, even with its hash recomputed
+	// without the key
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	var record auditRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	record.StatusCode = 401
+	record.Hash = ""
+	encoded, err := json.Marshal(record)
+	require.NoError(t, err)
+	digest := sha256.Sum256(encoded)
+	record.Hash = hex.EncodeToString(digest[:])
+	encoded, err = json.Marshal(record)
+	require.NoError(t, err)
+	lines[0] = string(encoded)
+	tampered := []byte(strings.Join(lines, "\n") + "\n")
+	_, err = verifyAuditLog(bytes.NewReader(tampered), []byte("audit-key"))
+	require.ErrorContains(t, err, "record in line 1 has been modified")
+
+	// Removing a record breaks the chain as well
+	removed := []byte(lines[1] + "\n")
+	_, err = verifyAuditLog(bytes.NewReader(removed), []byte("audit-key"))
+	require.ErrorContains(t, err, "record in line 1 does not continue the chain")
+
+	// The plugin refuses to continue a modified log
+	require.NoError(t, os.WriteFile(filename, tampered, 0o600))
+	require.ErrorContains(t, newPlugin().Init(), "record in line 1 has been modified")
+}
+
+func TestInitAuditLogWithoutKey(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.AuditLog = filepath.Join(t.TempDir(), "audit.log")
+	require.ErrorContains(t, plugin.Init(), "audit_log requires audit_key to be set")
+}
+
+func TestAuditLogHistoricalTrades(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	mux.Handle("/historicalTrades", tradesHandler(t, 5000, &requests))
+	mux.Handle("/", testdataHandler(t))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	filename := filepath.Join(t.TempDir(), "audit.log")
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("secret-key"))
+	plugin.HistoricalTrades = true
+	plugin.AuditLog = filename
+	plugin.AuditKey = config.NewSecret([]byte("audit-key"))
+	require.NoError(t, plugin.Init())
+	defer plugin.Stop()
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	// A full page of trades is followed by an empty one
+	require.Equal(t, 2, requests)
+
+	buf, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	require.Len(t, lines, 2)
+
+	var prev string
+	for _, line := range lines {
+		require.NotContains(t, line, "secret-key")
+
+		var record auditRecord
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		require.Equal(t, "GET", record.Method)
+		require.Equal(t, "/historicalTrades", record.Endpoint)
+		require.Equal(t, 200, record.StatusCode)
+		require.Equal(t, "BTCEUR", record.Params["symbol"])
+		require.Equal(t, prev, record.PrevHash)
+		prev = record.Hash
+	}
+	hash, err := verifyAuditLog(bytes.NewReader(buf), []byte("audit-key"))
+	require.NoError(t, err)
+	require.Equal(t, prev, hash)
+}
+
+func TestDebugHTTP(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(signedHandler(t, "my-api-key", "secret"))
+	defer sapi.Close()
+
+	logger := &testutil.CaptureLogger{}
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("my-api-key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.AccountInfo = true
+	plugin.DebugHTTP = true
+	plugin.Log = logger
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	var requests, responses int
+	for _, m := range logger.Messages() {
+		require.NotContains(t, m.Text, "my-api-key")
+		if !strings.Contains(m.Text, "/account/info") {
+			continue
+		}
+		require.Contains(t, m.Text, "signature="+redacted)
+		if strings.HasPrefix(m.Text, "HTTP request:") {
+			requests++
+			require.Contains(t, m.Text, "X-Mbx-Apikey:["+redacted+"]")
+		}
+		if strings.HasPrefix(m.Text, "HTTP response") {
+			responses++
+			require.Contains(t, m.Text, `"vipLevel": 1`)
+		}
+	}
+	require.Equal(t, 1, requests)
+	require.Equal(t, 1, responses)
+}
+
+func TestRedactBody(t *testing.T) {
+	body := `{"listenKey": "pqia91ma19a5s61cv6a81va65sdf19v8a65a1a5s61cv6a81va65sdf19v8a65a1","other":"value"}`
+	require.Equal(t, `{"listenKey": "REDACTED","other":"value"}`, string(redactBody([]byte(body))))
+}
+
+func TestAccountSnapshots(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	var starts []string
+	sapi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The snapshots of all wallet types share the endpoint
+		kind := r.URL.Query().Get("type")
+		starts = append(starts, kind+":"+r.URL.Query().Get("startTime"))
+		r.URL.Path += "_" + kind
+		signedHandler(t, "key", "secret")(w, r)
+	}))
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.AccountSnapshots = []string{"SPOT", "futures"}
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.SetState(state{LastSnapshot: map[string]int64{"spot": 1741651199000}}))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, []string{"SPOT:1741651199001", "FUTURES:"}, starts)
+
+	// The spot snapshot already reported before is skipped
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_account_snapshot",
+			map[string]string{"type": "spot"},
+			map[string]interface{}{"total_asset_btc": 0.6},
+			time.UnixMilli(1741737599000),
+		),
+		metric.New(
+			"binance_snapshot_balance",
+			map[string]string{"type": "spot", "asset": "BTC"},
+			map[string]interface{}{"free": 0.6, "locked": 0.0},
+			time.UnixMilli(1741737599000),
+		),
+		metric.New(
+			"binance_snapshot_balance",
+			map[string]string{"type": "futures", "asset": "USDT"},
+			map[string]interface{}{"margin_balance": 118.99782335, "wallet_balance": 120.23811389},
+			time.UnixMilli(1741737599000),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_account_snapshot" || m.Name() == "binance_snapshot_balance" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+
+	s, ok := plugin.GetState().(state)
+	require.True(t, ok)
+	require.Equal(t, map[string]int64{"spot": 1741737599000, "futures": 1741737599000}, s.LastSnapshot)
+
+	// The snapshots are not queried again within the hour
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, starts, 2)
+}
+
+func TestInitInvalidAccountSnapshotType(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.AccountSnapshots = []string{"options"}
+	require.ErrorContains(t, plugin.Init(), `invalid account snapshot type "options"`)
+}
+
+func TestInitAccountWithoutCredentials(t *testing.T) {
+	plugin := newTestPlugin("")
+	plugin.AccountBalances = true
+	require.ErrorContains(t, plugin.Init(), "account balances require api_key and api_secret or private_key_file to be set")
+
+	plugin = newTestPlugin("")
+	plugin.OpenOrders = true
+	require.ErrorContains(t, plugin.Init(), "open orders require api_key and api_secret or private_key_file to be set")
+
+	plugin = newTestPlugin("")
+	plugin.MyTrades = true
+	require.ErrorContains(t, plugin.Init(), "fills require api_key and api_secret or private_key_file to be set")
+
+	plugin = newTestPlugin("")
+	plugin.TradeFees = true
+	require.ErrorContains(t, plugin.Init(), "trade fees require api_key and api_secret or private_key_file to be set")
+
+	plugin = newTestPlugin("")
+	plugin.WithdrawalHistory = true
+	require.ErrorContains(t, plugin.Init(), "transfer history requires api_key and api_secret or private_key_file to be set")
+
+	plugin = newTestPlugin("")
+	plugin.MarginAccount = true
+	require.ErrorContains(t, plugin.Init(), "margin account requires api_key and api_secret or private_key_file to be set")
+
+	plugin = newTestPlugin("")
+	plugin.CryptoLoans = true
+	require.ErrorContains(t, plugin.Init(), "crypto loans require api_key and api_secret or private_key_file to be set")
+
+	plugin = newTestPlugin("")
+	plugin.SimpleEarn = true
+	require.ErrorContains(t, plugin.Init(), "positions of Simple Earn require api_key and api_secret or private_key_file to be set")
+
+	plugin = newTestPlugin("")
+	plugin.Staking = []string{"ETH"}
+	require.ErrorContains(t, plugin.Init(), "staking requires api_key and api_secret or private_key_file to be set")
+
+	plugin = newTestPlugin("")
+	plugin.SubAccounts = true
+	require.ErrorContains(t, plugin.Init(), "sub-accounts require api_key and api_secret or private_key_file to be set")
+
+	plugin = newTestPlugin("")
+	plugin.KeyPermissions = true
+	require.ErrorContains(t, plugin.Init(), "API key permissions require api_key and api_secret or private_key_file to be set")
+
+	plugin = newTestPlugin("")
+	plugin.CommissionRates = true
+	require.ErrorContains(t, plugin.Init(), "commission rates require api_key and api_secret or private_key_file to be set")
+
+	plugin = newTestPlugin("")
+	plugin.AccountInfo = true
+	require.ErrorContains(t, plugin.Init(), "account information requires api_key and api_secret or private_key_file to be set")
+
+	plugin = newTestPlugin("")
+	plugin.PortfolioValuation = "USDT"
+	require.ErrorContains(t, plugin.Init(), "portfolio_valuation requires account_balances to be enabled")
+}
+
+func TestMarginInterest(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	sapi := httptest.NewServer(signedHandler(t, "key", "secret"))
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.MarginAssets = []string{"btc"}
+	plugin.MarginIsolated = true
+	plugin.MarginVipLevel = -1
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_margin_interest",
+			map[string]string{"mode": "cross", "asset": "BTC", "vip_level": "0"},
+			map[string]interface{}{
+				"daily_interest_rate":  0.00024,
+				"hourly_interest_rate": 0.00001,
+				"yearly_interest_rate": 0.0876,
+				"borrow_limit":         180.0,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_margin_interest",
+			map[string]string{"mode": "isolated", "base": "BTC", "quote": "EUR", "asset": "BTC", "vip_level": "0"},
+			map[string]interface{}{
+				"daily_interest_rate":  0.00048,
+				"hourly_interest_rate": 0.00002,
+				"borrow_limit":         5.0,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_margin_interest",
+			map[string]string{"mode": "isolated", "base": "BTC", "quote": "EUR", "asset": "EUR", "vip_level": "0"},
+			map[string]interface{}{
+				"daily_interest_rate":  0.00036,
+				"hourly_interest_rate": 0.000015,
+				"borrow_limit":         100000.0,
+			},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() == "binance_margin_interest" {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), cmpopts.EquateApprox(0, 1e-12))
+}
+
+func TestMarginMaxBorrowable(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	var isolatedSymbols []string
+	sapi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == maxBorrowableEndpoint {
+			isolatedSymbols = append(isolatedSymbols, r.URL.Query().Get("isolatedSymbol"))
+		}
+		signedHandler(t, "key", "secret")(w, r)
+	}))
+	defer sapi.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.MarginAssets = []string{"BTC"}
+	plugin.MarginIsolated = true
+	plugin.MarginMaxBorrowable = true
+	plugin.MarginVipLevel = -1
+	plugin.sapiURL = sapi.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Equal(t, []string{"", "BTCEUR", "BTCEUR"}, isolatedSymbols)
+
+	var count int
+	for _, m := range acc.GetTelegrafMetrics() {
+		if m.Name() != "binance_margin_interest" {
+			continue
+		}
+		count++
+		borrowable, found := m.GetField("max_borrowable")
+		require.True(t, found)
+		require.InDelta(t, 1.69248805, borrowable, 1e-12)
+		limit, found := m.GetField("account_borrow_limit")
+		require.True(t, found)
+		require.InDelta(t, 60.0, limit, 1e-12)
+	}
+	require.Equal(t, 3, count)
+}
+
+func TestInitMarginRequiresSecret(t *testing.T) {
+	plugin := newTestPlugin("http://localhost")
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.MarginIsolated = true
+	require.ErrorContains(t, plugin.Init(), "margin interest rates require api_key and api_secret or private_key_file to be set")
+}
+
+func TestLeverageBrackets(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	futures := httptest.NewServer(signedHandler(t, "key", "secret"))
+	defer futures.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.APIKey = config.NewSecret([]byte("key"))
+	plugin.APISecret = config.NewSecret([]byte("secret"))
+	plugin.LeverageBrackets = []string{"btcusdt"}
+	plugin.futuresURL = futures.URL
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_leverage_bracket",
+			map[string]string{"symbol": "BTCUSDT", "bracket": "1"},
+			map[string]interface{}{
+				"initial_leverage":   125,
+				"notional_cap":       50000.0,
+				"notional_floor":     0.0,
+				"maint_margin_ratio": 0.004,
+				"cum":                0.0,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_leverage_bracket",
+			map[string]string{"symbol": "BTCUSDT", "bracket": "2"},
+			map[string]interface{}{
+				"initial_leverage":   100,
+				"notional_cap":       600000.0,
+				"notional_floor":     50000.0,
+				"maint_margin_ratio": 0.005,
+				"cum":                50.0,
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"binance_leverage_brackets",
+			map[string]string{"symbol": "BTCUSDT"},
+			map[string]interface{}{"brackets": 2, "changed": false},
+			time.Unix(0, 0),
+		),
+	}
+	var actual []telegraf.Metric
+	for _, m := range acc.GetTelegrafMetrics() {
+		if strings.HasPrefix(m.Name(), "binance_leverage") {
+			actual = append(actual, m)
+		}
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+
+	// Simulate a change of the brackets
+	plugin.leverageBracketsSeen["BTCUSDT"] = "[]"
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	changed, found := acc.BoolField("binance_leverage_brackets", "changed")
+	require.True(t, found)
+	require.True(t, changed)
+}
@@ -0,0 +1,87 @@
+package binance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// addBookStats emits the statistics derived from the given order-book levels
+// sorted from the best price on.
+func (b *Binance) addBookStats(acc telegraf.Accumulator, p *pair, bids, asks []bookLevel, ts time.Time) {
+	if len(bids) == 0 || len(asks) == 0 {
+		acc.AddError(fmt.Errorf("order book of %s has an empty side", p.symbol))
+		return
+	}
+
+	mid := (bids[0].price + asks[0].price) / 2
+	spread := asks[0].price - bids[0].price
+	fields := map[string]interface{}{
+		"best_bid": bids[0].price,
+		"best_ask": asks[0].price,
+		"mid":      mid,
+		"spread":   spread,
+	}
+	if mid > 0 {
+		fields["spread_bps"] = spread / mid * 10000
+	}
+
+	// The microprice weights the best prices by the quantity on the opposite
+	// side, i.e. moves towards the side likely to be traded through next.
+	if total := bids[0].quantity + asks[0].quantity; total > 0 {
+		fields["microprice"] = (bids[0].price*asks[0].quantity + asks[0].price*bids[0].quantity) / total
+	}
+
+	// Share of the bid quantity in the quantity of both sides and the
+	// imbalance between them ranging from -1 (asks only) to 1 (bids only)
+	n := len(bids)
+	if b.DepthPressureLevels > 0 {
+		n = b.DepthPressureLevels
+	}
+	bidQuantity, askQuantity := quantity(bids, n), quantity(asks, n)
+	if total := bidQuantity + askQuantity; total > 0 {
+		fields["pressure"] = bidQuantity / total
+		fields["imbalance"] = (bidQuantity - askQuantity) / total
+	}
+
+	// Total notional value of the collected levels
+	all := func(float64) bool { return true }
+	fields["bid_value"] = notional(bids, all)
+	fields["ask_value"] = notional(asks, all)
+
+	// Notional value available within the given distance from the mid price
+	for _, bps := range b.DepthBps {
+		distance := mid * float64(bps) / 10000
+		bidNotional := notional(bids, func(price float64) bool { return price >= mid-distance })
+		askNotional := notional(asks, func(price float64) bool { return price <= mid+distance })
+		fields[fmt.Sprintf("bid_notional_%dbps", bps)] = bidNotional
+		fields[fmt.Sprintf("ask_notional_%dbps", bps)] = askNotional
+		if total := bidNotional + askNotional; total > 0 {
+			fields[fmt.Sprintf("imbalance_%dbps", bps)] = (bidNotional - askNotional) / total
+		}
+	}
+	acc.AddFields("binance_book", fields, p.tags, ts)
+}
+
+// quantity sums up the quantity of the first n levels
+func quantity(levels []bookLevel, n int) float64 {
+	var sum float64
+	for _, l := range levels[:min(n, len(levels))] {
+		sum += l.quantity
+	}
+	return sum
+}
+
+// notional sums up the value of the levels from the best price on as long as
+// the price is within range
+func notional(levels []bookLevel, within func(float64) bool) float64 {
+	var sum float64
+	for _, l := range levels {
+		if !within(l.price) {
+			break
+		}
+		sum += l.price * l.quantity
+	}
+	return sum
+}
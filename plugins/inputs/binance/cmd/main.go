@@ -0,0 +1,40 @@
+// Command binance runs the Binance input plugin as an external plugin to be
+// used with the inputs.execd plugin of an unmodified Telegraf binary.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/influxdata/telegraf/plugins/common/shim"
+	_ "github.com/influxdata/telegraf/plugins/inputs/binance" // register plugin
+)
+
+var pollInterval = flag.Duration("poll_interval", 1*time.Second, "how often to send metrics")
+
+var pollIntervalDisabled = flag.Bool(
+	"poll_interval_disabled",
+	false,
+	"set to true to disable polling and gather on receiving a newline on STDIN only",
+)
+var configFile = flag.String("config", "", "path to the config file for this plugin")
+
+func main() {
+	flag.Parse()
+	if *pollIntervalDisabled {
+		*pollInterval = shim.PollIntervalDisabled
+	}
+
+	shimLayer := shim.New()
+	if err := shimLayer.LoadConfig(configFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Err loading input: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := shimLayer.Run(*pollInterval); err != nil {
+		fmt.Fprintf(os.Stderr, "Err: %s\n", err)
+		os.Exit(1)
+	}
+}
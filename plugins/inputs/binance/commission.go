@@ -0,0 +1,86 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	commissionEndpoint string = "/account/commission"
+	commissionWeight   int64  = 20
+)
+
+type commissionRates struct {
+	Maker string `json:"maker"`
+	Taker string `json:"taker"`
+}
+
+type accountCommission struct {
+	Symbol             string          `json:"symbol"`
+	StandardCommission commissionRates `json:"standardCommission"`
+	SpecialCommission  commissionRates `json:"specialCommission"`
+	TaxCommission      commissionRates `json:"taxCommission"`
+	Discount           struct {
+		EnabledForAccount bool   `json:"enabledForAccount"`
+		EnabledForSymbol  bool   `json:"enabledForSymbol"`
+		DiscountAsset     string `json:"discountAsset"`
+		Discount          string `json:"discount"`
+	} `json:"discount"`
+}
+
+// gatherCommissionRates emits the commission rates of the account for the
+// pairs split into their components and the effective rates, assuming the
+// commission is paid in the discount asset if the discount applies
+func (b *Binance) gatherCommissionRates(acc telegraf.Accumulator) {
+	for _, p := range b.pairs {
+		acc.AddError(b.gatherPairCommissionRates(acc, p))
+	}
+}
+
+func (b *Binance) gatherPairCommissionRates(acc telegraf.Accumulator, p *pair) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var c accountCommission
+	if err := b.querySigned(ctx, b.apiURL+commissionEndpoint, p.query(), commissionWeight, &c); err != nil {
+		return err
+	}
+
+	fields := make(map[string]interface{}, 10)
+	err := parseFloatFields(fields, map[string]string{
+		"standard_maker": c.StandardCommission.Maker,
+		"standard_taker": c.StandardCommission.Taker,
+		"special_maker":  c.SpecialCommission.Maker,
+		"special_taker":  c.SpecialCommission.Taker,
+		"tax_maker":      c.TaxCommission.Maker,
+		"tax_taker":      c.TaxCommission.Taker,
+		"discount":       c.Discount.Discount,
+	})
+	if err != nil {
+		return fmt.Errorf("parsing commission rates of %s failed: %w", p.symbol, err)
+	}
+
+	// The discount only reduces the standard commission
+	discounted := c.Discount.EnabledForAccount && c.Discount.EnabledForSymbol
+	factor := 1.0
+	if discounted {
+		factor, _ = fields["discount"].(float64)
+	}
+	for _, side := range []string{"maker", "taker"} {
+		standard, _ := fields["standard_"+side].(float64)
+		special, _ := fields["special_"+side].(float64)
+		tax, _ := fields["tax_"+side].(float64)
+		fields["effective_"+side] = standard*factor + special + tax
+	}
+	fields["discount_enabled"] = discounted
+
+	tags := p.tags
+	if c.Discount.DiscountAsset != "" {
+		tags = p.tagsWith("discount_asset", c.Discount.DiscountAsset)
+	}
+	acc.AddFields("binance_commission", fields, tags)
+	return nil
+}
@@ -0,0 +1,97 @@
+package binance
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// conversion of prices in a quote asset to the asset given in convert_to
+// using the price of a cross pair
+type conversion struct {
+	symbol string
+	// The cross pair quotes the target asset in the quote asset, e.g. EURUSDT
+	// for converting USDT prices to EUR, so prices are divided by its price.
+	divide bool
+}
+
+// resolveConversions finds the cross pairs for converting the prices of all
+// pairs to the convert_to asset. Pairs without a cross pair are reported
+// without conversion.
+func (b *Binance) resolveConversions() error {
+	b.conversions = make(map[string]conversion)
+	for _, p := range b.pairs {
+		quote := p.priceTags["quote"]
+		if _, found := b.conversions[quote]; found || quote == b.ConvertTo {
+			continue
+		}
+
+		c, found, err := b.findConversion(quote)
+		if err != nil {
+			return fmt.Errorf("resolving conversion of %s to %s failed: %w", quote, b.ConvertTo, err)
+		}
+		if !found {
+			b.Log.Warnf("No cross pair for converting %s to %s, skipping conversion", quote, b.ConvertTo)
+			continue
+		}
+		b.Log.Debugf("Converting %s to %s using %s", quote, b.ConvertTo, c.symbol)
+		b.conversions[quote] = c
+	}
+	return nil
+}
+
+func (b *Binance) findConversion(quote string) (conversion, bool, error) {
+	candidates := []conversion{
+		{symbol: b.ConvertTo + quote, divide: true},
+		{symbol: quote + b.ConvertTo},
+	}
+
+	// Avoid querying the exchange for cross pairs collected anyway
+	for _, c := range candidates {
+		if slices.ContainsFunc(b.pairs, func(p *pair) bool { return p.symbol == c.symbol }) {
+			return c, true, nil
+		}
+	}
+	for _, c := range candidates {
+		_, err := b.symbolInfo(c.symbol)
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.Code == codeInvalidSymbol {
+			continue
+		}
+		if err != nil {
+			return conversion{}, false, err
+		}
+		return c, true, nil
+	}
+	return conversion{}, false, nil
+}
+
+// convert returns the given price of the pair in the convert_to asset using
+// the given prices of the cross pairs
+func (b *Binance) convert(p *pair, price float64, ticks map[string]tick) (float64, error) {
+	quote := p.priceTags["quote"]
+	if quote == b.ConvertTo {
+		return price, nil
+	}
+	c, found := b.conversions[quote]
+	if !found {
+		return 0, nil
+	}
+	t, found := ticks[c.symbol]
+	if !found {
+		return 0, fmt.Errorf("no price received for cross pair %s", c.symbol)
+	}
+	cross, err := strconv.ParseFloat(strings.TrimSpace(t.Price), 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse price %s of cross pair %s: %w", t.Price, c.symbol, err)
+	}
+	if c.divide {
+		if cross == 0 {
+			return 0, fmt.Errorf("zero price of cross pair %s", c.symbol)
+		}
+		return price / cross, nil
+	}
+	return price * cross, nil
+}
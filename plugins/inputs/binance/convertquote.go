@@ -0,0 +1,111 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	convertQuoteEndpoint string = "/convert/getQuote"
+	convertQuoteWeight   int64  = 200
+)
+
+// Conversion to request a Binance Convert quote for
+type convertQuote struct {
+	FromAsset string  `toml:"from_asset"`
+	ToAsset   string  `toml:"to_asset"`
+	Amount    float64 `toml:"amount"`
+}
+
+func (q *convertQuote) init() error {
+	if q.FromAsset == "" || q.ToAsset == "" {
+		return errors.New("convert quote from_asset and to_asset cannot be empty")
+	}
+	if q.Amount <= 0 {
+		return fmt.Errorf("convert quote amount %v must be positive", q.Amount)
+	}
+	q.FromAsset = strings.ToUpper(q.FromAsset)
+	q.ToAsset = strings.ToUpper(q.ToAsset)
+	return nil
+}
+
+type convertQuoteResponse struct {
+	Ratio        string `json:"ratio"`
+	InverseRatio string `json:"inverseRatio"`
+	ToAmount     string `json:"toAmount"`
+}
+
+// gatherConvertQuotes emits the quoted rates of the configured conversions
+// and their spread to the spot prices. The quotes are never accepted.
+func (b *Binance) gatherConvertQuotes(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var ticks []tick
+	if err := b.query(ctx, priceEndpoint, nil, multiPriceWeight, &ticks); err != nil {
+		return fmt.Errorf("querying spot prices failed: %w", err)
+	}
+	for _, q := range b.ConvertQuotes {
+		acc.AddError(b.gatherConvertQuote(ctx, acc, q, ticks))
+	}
+	return nil
+}
+
+func (b *Binance) gatherConvertQuote(ctx context.Context, acc telegraf.Accumulator, q *convertQuote, ticks []tick) error {
+	params := url.Values{
+		"fromAsset":  {q.FromAsset},
+		"toAsset":    {q.ToAsset},
+		"fromAmount": {strconv.FormatFloat(q.Amount, 'f', -1, 64)},
+	}
+	var resp convertQuoteResponse
+	if err := b.postSigned(ctx, b.sapiURL+convertQuoteEndpoint, params, convertQuoteWeight, &resp); err != nil {
+		return fmt.Errorf("requesting quote for converting %s to %s failed: %w", q.FromAsset, q.ToAsset, err)
+	}
+
+	fields := map[string]interface{}{"amount": q.Amount}
+	err := parseFloatFields(fields, map[string]string{
+		"ratio":         resp.Ratio,
+		"inverse_ratio": resp.InverseRatio,
+		"to_amount":     resp.ToAmount,
+	})
+	if err != nil {
+		return fmt.Errorf("parsing quote for converting %s to %s failed: %w", q.FromAsset, q.ToAsset, err)
+	}
+	// The spread is positive if the quote is worse than the spot price
+	if spot, found := spotRatio(ticks, q.FromAsset, q.ToAsset); found {
+		ratio, _ := fields["ratio"].(float64)
+		fields["spot_ratio"] = spot
+		fields["spread_percent"] = (spot - ratio) / spot * 100
+	} else {
+		b.Log.Debugf("No spot price of %s in %s, skipping the spread", q.FromAsset, q.ToAsset)
+	}
+
+	tags := map[string]string{
+		"from_asset": q.FromAsset,
+		"to_asset":   q.ToAsset,
+	}
+	acc.AddFields("binance_convert_quote", fields, tags)
+	return nil
+}
+
+// spotRatio returns the spot price of the from-asset in the to-asset, using
+// the inverse pair or the USDT pairs of both assets if needed
+func spotRatio(ticks []tick, from, to string) (float64, bool) {
+	if price, found := pricesIn(ticks, to)[from]; found {
+		return price, true
+	}
+	usd := pricesIn(ticks, usdQuote)
+	fromPrice, fromFound := usd[from]
+	toPrice, toFound := usd[to]
+	if !fromFound || !toFound {
+		return 0, false
+	}
+	return fromPrice / toPrice, true
+}
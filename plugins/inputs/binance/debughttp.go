@@ -0,0 +1,77 @@
+package binance
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const redacted string = "REDACTED"
+
+// Query parameters and JSON fields carrying credentials
+var sensitiveParams = []string{"signature", "listenKey", "apiKey", "secretKey"}
+
+var sensitiveFields = regexp.MustCompile(`("(?:` + strings.Join(sensitiveParams, "|") + `)"\s*:\s*)"[^"]*"`)
+
+// redactURL returns the URL with the values of all credential parameters
+// replaced, keeping the order of the parameters.
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	parts := strings.Split(u.RawQuery, "&")
+	for i, part := range parts {
+		name, _, found := strings.Cut(part, "=")
+		if found && isSensitiveParam(name) {
+			parts[i] = name + "=" + redacted
+		}
+	}
+	redactedURL := *u
+	redactedURL.RawQuery = strings.Join(parts, "&")
+	return redactedURL.String()
+}
+
+func isSensitiveParam(name string) bool {
+	for _, p := range sensitiveParams {
+		if strings.EqualFold(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeader returns a copy of the header without the API key
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	if h.Get("X-MBX-APIKEY") != "" {
+		h.Set("X-MBX-APIKEY", redacted)
+	}
+	return h
+}
+
+// redactBody replaces the values of credential fields in a JSON body, e.g.
+// the listenKey of a user data stream.
+func redactBody(body []byte) []byte {
+	return sensitiveFields.ReplaceAll(body, []byte(`$1"`+redacted+`"`))
+}
+
+// dumpRequest logs the request with all credentials redacted
+func (b *Binance) dumpRequest(r *http.Request) {
+	b.Log.Debugf("HTTP request: %s %s, header: %v", r.Method, redactURL(r.URL), redactHeader(r.Header))
+}
+
+// dumpResponse logs the response with all credentials redacted and restores
+// its body for decoding.
+func (b *Binance) dumpResponse(r *http.Request, resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	b.Log.Debugf("HTTP response for %s %s: %s, header: %v, body: %s",
+		r.Method, redactURL(r.URL), resp.Status, resp.Header, redactBody(body))
+	return nil
+}
@@ -0,0 +1,130 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const depthEndpoint string = "/depth"
+
+// Layouts for emitting the order-book levels
+const (
+	depthLayoutAggregate string = "aggregate"
+	depthLayoutPerLevel  string = "per_level"
+)
+
+type depthSnapshot struct {
+	LastUpdateID int64       `json:"lastUpdateId"`
+	Bids         [][2]string `json:"bids"`
+	Asks         [][2]string `json:"asks"`
+}
+
+// bookLevel is a price level of the order book
+type bookLevel struct {
+	price    float64
+	quantity float64
+}
+
+// depthWeight returns the request weight of a depth snapshot with the given
+// number of levels
+func depthWeight(limit int) int64 {
+	switch {
+	case limit <= 100:
+		return 5
+	case limit <= 500:
+		return 25
+	case limit <= 1000:
+		return 50
+	}
+	return 250
+}
+
+// gatherDepth emits the top levels of the order book for all pairs
+func (b *Binance) gatherDepth(acc telegraf.Accumulator) {
+	for _, p := range b.pairs {
+		acc.AddError(b.gatherPairDepth(acc, p))
+	}
+}
+
+func (b *Binance) gatherPairDepth(acc telegraf.Accumulator, p *pair) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	params := p.query()
+	params.Set("limit", strconv.Itoa(b.DepthLimit))
+	var snapshot depthSnapshot
+	if err := b.query(ctx, depthEndpoint, params, depthWeight(b.DepthLimit), &snapshot); err != nil {
+		return err
+	}
+
+	bids, err := parseBookLevels(snapshot.Bids)
+	if err != nil {
+		return fmt.Errorf("parsing bids of %s failed: %w", p.symbol, err)
+	}
+	asks, err := parseBookLevels(snapshot.Asks)
+	if err != nil {
+		return fmt.Errorf("parsing asks of %s failed: %w", p.symbol, err)
+	}
+
+	now := time.Now()
+	b.addBookStats(acc, p, bids, asks, now)
+	b.addSlippage(acc, p, bids, asks, now)
+
+	sides := []struct {
+		name   string
+		levels []bookLevel
+	}{{"bid", bids}, {"ask", asks}}
+
+	// The cumulative quantity is the quantity available from the best price
+	// up to and including the level
+	if b.DepthLayout == depthLayoutPerLevel {
+		for _, side := range sides {
+			var cumulative float64
+			for i, l := range side.levels {
+				cumulative += l.quantity
+				tags := p.tagsWith("side", side.name)
+				tags["level"] = strconv.Itoa(i)
+				fields := map[string]interface{}{
+					"price":               l.price,
+					"quantity":            l.quantity,
+					"cumulative_quantity": cumulative,
+				}
+				acc.AddFields("binance_depth", fields, tags, now)
+			}
+		}
+		return nil
+	}
+
+	fields := map[string]interface{}{"last_update_id": snapshot.LastUpdateID}
+	for _, side := range sides {
+		var cumulative float64
+		for i, l := range side.levels {
+			cumulative += l.quantity
+			fields[fmt.Sprintf("%s_price_%d", side.name, i)] = l.price
+			fields[fmt.Sprintf("%s_quantity_%d", side.name, i)] = l.quantity
+			fields[fmt.Sprintf("%s_cumulative_quantity_%d", side.name, i)] = cumulative
+		}
+	}
+	acc.AddFields("binance_depth", fields, p.tags, now)
+	return nil
+}
+
+func parseBookLevels(raw [][2]string) ([]bookLevel, error) {
+	levels := make([]bookLevel, 0, len(raw))
+	for _, r := range raw {
+		price, err := strconv.ParseFloat(r[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse price %q: %w", r[0], err)
+		}
+		quantity, err := strconv.ParseFloat(r[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse quantity %q: %w", r[1], err)
+		}
+		levels = append(levels, bookLevel{price: price, quantity: quantity})
+	}
+	return levels, nil
+}
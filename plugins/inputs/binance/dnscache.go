@@ -0,0 +1,95 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf/selfstat"
+)
+
+// dnsCache keeps the resolved addresses of hosts for the given time-to-live
+// to avoid a DNS lookup per connection while still picking up DNS-based
+// failovers once the entry expired.
+type dnsCache struct {
+	ttl    time.Duration
+	lookup func(ctx context.Context, host string) ([]net.IPAddr, error)
+	hits   selfstat.Stat
+
+	entries map[string]dnsEntry
+	sync.Mutex
+}
+
+type dnsEntry struct {
+	addrs    []net.IPAddr
+	resolved time.Time
+}
+
+func newDNSCache(ttl time.Duration) *dnsCache {
+	return &dnsCache{
+		ttl:     ttl,
+		lookup:  net.DefaultResolver.LookupIPAddr,
+		hits:    selfstat.Register("binance", "dns_cache_hits", nil),
+		entries: make(map[string]dnsEntry),
+	}
+}
+
+// resolve returns the addresses of the host, resolving it if the cached
+// entry is missing or expired. If resolving fails, an expired entry is used
+// until the host can be resolved again.
+func (c *dnsCache) resolve(ctx context.Context, host string) ([]net.IPAddr, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	entry, found := c.entries[host]
+	if found && time.Since(entry.resolved) < c.ttl {
+		c.hits.Incr(1)
+		return entry.addrs, nil
+	}
+
+	start := time.Now()
+	addrs, err := c.lookup(ctx, host)
+	selfstat.RegisterTiming("binance", "dns_lookup_ns", map[string]string{"host": host}).Incr(time.Since(start).Nanoseconds())
+	if err != nil {
+		if found {
+			return entry.addrs, nil
+		}
+		return nil, err
+	}
+	c.entries[host] = dnsEntry{addrs: addrs, resolved: time.Now()}
+	return addrs, nil
+}
+
+// dial connects to the first reachable of the cached addresses of the host
+// matching the IP version
+func (c *dnsCache) dial(ctx context.Context, d *net.Dialer, network, address, version string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if net.ParseIP(host) != nil {
+		return d.DialContext(ctx, network, address)
+	}
+
+	addrs, err := c.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error
+	for _, addr := range addrs {
+		if !isVersion(addr.IP, version) {
+			continue
+		}
+		conn, err := d.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		return nil, fmt.Errorf("no suitable address found for %s", host)
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,122 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	earnFlexiblePositionEndpoint string = "/simple-earn/flexible/position"
+	earnLockedPositionEndpoint   string = "/simple-earn/locked/position"
+	earnPositionWeight           int64  = 150
+	// Maximum number of positions returned per request
+	earnPositionLimit int = 100
+)
+
+type earnFlexiblePositions struct {
+	Rows []struct {
+		Asset                      string `json:"asset"`
+		ProductID                  string `json:"productId"`
+		TotalAmount                string `json:"totalAmount"`
+		LatestAnnualPercentageRate string `json:"latestAnnualPercentageRate"`
+		YesterdayRealTimeRewards   string `json:"yesterdayRealTimeRewards"`
+		CumulativeTotalRewards     string `json:"cumulativeTotalRewards"`
+	} `json:"rows"`
+	Total int `json:"total"`
+}
+
+type earnLockedPositions struct {
+	Rows []struct {
+		PositionID  int64  `json:"positionId"`
+		ProjectID   string `json:"projectId"`
+		Asset       string `json:"asset"`
+		Amount      string `json:"amount"`
+		APY         string `json:"APY"`
+		RewardAmt   string `json:"rewardAmt"`
+		Duration    string `json:"duration"`
+		AccrualDays string `json:"accrualDays"`
+	} `json:"rows"`
+	Total int `json:"total"`
+}
+
+// gatherSimpleEarn emits the principal, rewards and annual rate of the
+// flexible and locked Simple Earn positions of the account
+func (b *Binance) gatherSimpleEarn(acc telegraf.Accumulator) {
+	acc.AddError(b.gatherEarnFlexible(acc))
+	acc.AddError(b.gatherEarnLocked(acc))
+}
+
+func (b *Binance) gatherEarnFlexible(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	params := url.Values{"size": {strconv.Itoa(earnPositionLimit)}}
+	var positions earnFlexiblePositions
+	if err := b.querySigned(ctx, b.sapiURL+earnFlexiblePositionEndpoint, params, earnPositionWeight, &positions); err != nil {
+		return err
+	}
+	if positions.Total > len(positions.Rows) {
+		b.Log.Warnf("Reporting only %d of %d flexible Simple Earn positions", len(positions.Rows), positions.Total)
+	}
+
+	for _, p := range positions.Rows {
+		fields := make(map[string]interface{}, 4)
+		err := parseFloatFields(fields, map[string]string{
+			"principal":         p.TotalAmount,
+			"apr":               p.LatestAnnualPercentageRate,
+			"accrued_rewards":   p.CumulativeTotalRewards,
+			"yesterday_rewards": p.YesterdayRealTimeRewards,
+		})
+		if err != nil {
+			return fmt.Errorf("parsing flexible Simple Earn position %s failed: %w", p.ProductID, err)
+		}
+		tags := map[string]string{
+			"type":    "flexible",
+			"asset":   p.Asset,
+			"product": p.ProductID,
+		}
+		acc.AddFields("binance_earn", fields, tags)
+	}
+	return nil
+}
+
+func (b *Binance) gatherEarnLocked(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	params := url.Values{"size": {strconv.Itoa(earnPositionLimit)}}
+	var positions earnLockedPositions
+	if err := b.querySigned(ctx, b.sapiURL+earnLockedPositionEndpoint, params, earnPositionWeight, &positions); err != nil {
+		return err
+	}
+	if positions.Total > len(positions.Rows) {
+		b.Log.Warnf("Reporting only %d of %d locked Simple Earn positions", len(positions.Rows), positions.Total)
+	}
+
+	for _, p := range positions.Rows {
+		fields := make(map[string]interface{}, 5)
+		err := parseFloatFields(fields, map[string]string{
+			"principal":       p.Amount,
+			"apr":             p.APY,
+			"accrued_rewards": p.RewardAmt,
+			"duration_days":   p.Duration,
+			"accrual_days":    p.AccrualDays,
+		})
+		if err != nil {
+			return fmt.Errorf("parsing locked Simple Earn position %d failed: %w", p.PositionID, err)
+		}
+		tags := map[string]string{
+			"type":     "locked",
+			"asset":    p.Asset,
+			"product":  p.ProjectID,
+			"position": strconv.FormatInt(p.PositionID, 10),
+		}
+		acc.AddFields("binance_earn", fields, tags)
+	}
+	return nil
+}
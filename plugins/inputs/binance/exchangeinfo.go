@@ -0,0 +1,72 @@
+package binance
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// Symbol states always reported, even without any symbol in that state
+var summaryStates = []string{"TRADING", "BREAK", "HALT"}
+
+// gatherExchangeInfoSummary emits the number of symbols listed on the
+// exchange in total and per quote asset split by their trading status, e.g.
+// to detect mass trading halts.
+func (b *Binance) gatherExchangeInfoSummary(acc telegraf.Accumulator) error {
+	if time.Since(b.exchangeInfoQueried) < time.Duration(b.ExchangeInfoRefresh) {
+		return nil
+	}
+	b.exchangeInfoQueried = time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+	var info exchangeInfo
+	if err := b.query(ctx, exchangeEndpoint, nil, exchangeInfoWeight, &info); err != nil {
+		return err
+	}
+
+	var total symbolCounts
+	quotes := make(map[string]*symbolCounts)
+	for _, s := range info.Symbols {
+		total.add(s.Status)
+		counts, found := quotes[s.QuoteAsset]
+		if !found {
+			counts = &symbolCounts{}
+			quotes[s.QuoteAsset] = counts
+		}
+		counts.add(s.Status)
+	}
+
+	acc.AddFields("binance_exchange_info", total.fields(), nil)
+	for quote, counts := range quotes {
+		acc.AddFields("binance_exchange_info_quote", counts.fields(), map[string]string{"quote": quote})
+	}
+	return nil
+}
+
+// symbolCounts is the number of symbols in total and per trading status
+type symbolCounts struct {
+	symbols int
+	states  map[string]int
+}
+
+func (c *symbolCounts) add(status string) {
+	if c.states == nil {
+		c.states = make(map[string]int)
+	}
+	c.symbols++
+	c.states[status]++
+}
+
+func (c *symbolCounts) fields() map[string]interface{} {
+	fields := map[string]interface{}{"symbols": c.symbols}
+	for _, status := range summaryStates {
+		fields[strings.ToLower(status)] = 0
+	}
+	for status, n := range c.states {
+		fields[strings.ToLower(status)] = n
+	}
+	return fields
+}
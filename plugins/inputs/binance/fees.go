@@ -0,0 +1,59 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	tradeFeeEndpoint string = "/asset/tradeFee"
+	tradeFeeWeight   int64  = 1
+)
+
+type tradeFee struct {
+	Symbol          string `json:"symbol"`
+	MakerCommission string `json:"makerCommission"`
+	TakerCommission string `json:"takerCommission"`
+}
+
+// gatherTradeFees emits the maker and taker commission rates the account pays
+// for the pairs, querying the rates of all symbols at once for multiple pairs.
+func (b *Binance) gatherTradeFees(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	params := url.Values{}
+	if len(b.pairs) == 1 {
+		params = b.pairs[0].query()
+	}
+	var fees []tradeFee
+	if err := b.querySigned(ctx, b.sapiURL+tradeFeeEndpoint, params, tradeFeeWeight, &fees); err != nil {
+		return err
+	}
+
+	bySymbol := make(map[string]tradeFee, len(fees))
+	for _, f := range fees {
+		bySymbol[f.Symbol] = f
+	}
+	for _, p := range b.pairs {
+		f, found := bySymbol[p.symbol]
+		if !found {
+			b.Log.Debugf("No trade fees returned for symbol %s", p.symbol)
+			continue
+		}
+		fields := make(map[string]interface{}, 2)
+		err := parseFloatFields(fields, map[string]string{
+			"maker_commission": f.MakerCommission,
+			"taker_commission": f.TakerCommission,
+		})
+		if err != nil {
+			return fmt.Errorf("parsing trade fees of %s failed: %w", p.symbol, err)
+		}
+		acc.AddFields("binance_trade_fee", fields, p.tags)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package binance
+
+import (
+	"fmt"
+
+	"github.com/influxdata/telegraf"
+)
+
+// addSymbolFilters emits the limits of the exchange for the price, quantity
+// and notional value of orders of the pair, e.g. to validate order sizes.
+func addSymbolFilters(acc telegraf.Accumulator, p *pair, info symbolInfo) error {
+	values := make(map[string]string)
+	for _, f := range info.Filters {
+		switch f.FilterType {
+		case "PRICE_FILTER":
+			values["min_price"] = f.MinPrice
+			values["max_price"] = f.MaxPrice
+			values["tick_size"] = f.TickSize
+		case "LOT_SIZE":
+			values["min_quantity"] = f.MinQty
+			values["max_quantity"] = f.MaxQty
+			values["step_size"] = f.StepSize
+		case "NOTIONAL":
+			values["min_notional"] = f.MinNotional
+			values["max_notional"] = f.MaxNotional
+		case "MIN_NOTIONAL":
+			// Predecessor of the NOTIONAL filter without a maximum
+			values["min_notional"] = f.MinNotional
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, len(values))
+	if err := parseFloatFields(fields, values); err != nil {
+		return fmt.Errorf("parsing filters of %s failed: %w", p.symbol, err)
+	}
+	acc.AddFields("binance_symbol_filters", fields, p.tags)
+	return nil
+}
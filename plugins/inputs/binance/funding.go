@@ -0,0 +1,182 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	fundingRateEndpoint string = "/fundingRate"
+	fundingRateWeight   int64  = 1
+	// Maximum number of settled funding rates returned per request
+	fundingRateLimit int = 100
+)
+
+// fundingRate is a settled funding rate of a perpetual contract. The COIN-M
+// futures API does not return the mark price.
+type fundingRate struct {
+	Symbol      string `json:"symbol"`
+	FundingTime int64  `json:"fundingTime"`
+	FundingRate string `json:"fundingRate"`
+	MarkPrice   string `json:"markPrice"`
+}
+
+// perpetual checks if the pair is a perpetual contract paying funding.
+// Pairs without contract type are taken as perpetual.
+func (p *pair) perpetual() bool {
+	contractType, found := p.tags["contract_type"]
+	return !found || contractType == "PERPETUAL"
+}
+
+// addFundingRates emits the funding rate of the current funding period and
+// the time of the next funding of the perpetual contracts.
+func (b *Binance) addFundingRates(acc telegraf.Accumulator, indices map[string]premiumIndex) {
+	for _, p := range b.pairs {
+		index, found := indices[p.symbol]
+		// Delivery contracts have no funding rate
+		if !found || !p.perpetual() || index.LastFundingRate == "" {
+			continue
+		}
+		fields := map[string]interface{}{"next_funding_time": index.NextFundingTime}
+		err := parseFloatFields(fields, map[string]string{
+			"funding_rate":  index.LastFundingRate,
+			"interest_rate": index.InterestRate,
+		})
+		if err != nil {
+			acc.AddError(fmt.Errorf("parsing funding rate of %s failed: %w", p.symbol, err))
+			continue
+		}
+		acc.AddFields("binance_funding", fields, p.tagsWith("type", "current"))
+	}
+}
+
+// gatherFundingHistory emits the funding rates of the perpetual contracts
+// settled since the last reported one, with the time of the funding. The
+// rates are only queried once the next funding time of the contract passed.
+func (b *Binance) gatherFundingHistory(acc telegraf.Accumulator, indices map[string]premiumIndex) {
+	for _, p := range b.pairs {
+		if p.perpetual() {
+			acc.AddError(b.gatherPairFundingHistory(acc, p, indices[p.symbol]))
+		}
+	}
+}
+
+func (b *Binance) gatherPairFundingHistory(acc telegraf.Accumulator, p *pair, index premiumIndex) error {
+	// Without a known last funding, start with the fundings settled from now
+	// on instead of replaying the most recent ones
+	last, found := b.state.LastFunding[p.symbol]
+	if !found {
+		b.state.LastFunding[p.symbol] = time.Now().UnixMilli()
+		b.setFundingDue(p, index)
+		return nil
+	}
+
+	// The history of a restarted plugin is queried once to catch up with the
+	// fundings settled meanwhile, afterwards only once the funding is due
+	if due, known := b.fundingDue[p.symbol]; known && time.Now().UnixMilli() < due {
+		return nil
+	}
+
+	params := p.query()
+	params.Set("limit", strconv.Itoa(fundingRateLimit))
+	params.Set("startTime", strconv.FormatInt(last+1, 10))
+	rates, err := b.fetchFundingRates(params)
+	if err != nil {
+		return err
+	}
+	if err := b.addSettledFundingRates(acc, p, rates); err != nil {
+		return err
+	}
+
+	// Keep waiting for a due funding not published yet
+	if due, known := b.fundingDue[p.symbol]; !known || b.state.LastFunding[p.symbol] >= due {
+		b.setFundingDue(p, index)
+	}
+	return nil
+}
+
+// setFundingDue remembers the next funding time of the contract to query the
+// settled funding rate after it passed
+func (b *Binance) setFundingDue(p *pair, index premiumIndex) {
+	if index.NextFundingTime > b.state.LastFunding[p.symbol] {
+		b.fundingDue[p.symbol] = index.NextFundingTime
+	} else {
+		delete(b.fundingDue, p.symbol)
+	}
+}
+
+// addSettledFundingRates emits the settled funding rates of the pair and
+// remembers the last one as reported. The rates are only emitted if all of
+// them can be parsed.
+func (b *Binance) addSettledFundingRates(acc telegraf.Accumulator, p *pair, rates []fundingRate) error {
+	parsed := make([]map[string]interface{}, 0, len(rates))
+	for _, r := range rates {
+		values := map[string]string{"funding_rate": r.FundingRate}
+		if r.MarkPrice != "" {
+			values["mark_price"] = r.MarkPrice
+		}
+		fields := make(map[string]interface{}, len(values))
+		if err := parseFloatFields(fields, values); err != nil {
+			return fmt.Errorf("parsing funding rate of %s failed: %w", p.symbol, err)
+		}
+		parsed = append(parsed, fields)
+	}
+
+	tags := p.tagsWith("type", "settled")
+	for i, r := range rates {
+		acc.AddFields("binance_funding", parsed[i], tags, time.UnixMilli(r.FundingTime))
+		b.state.LastFunding[p.symbol] = r.FundingTime
+	}
+	return nil
+}
+
+// exportFundingRates emits the funding rates of the pair settled within the
+// given range, paging through the range as required. It returns the number
+// of emitted rates.
+func (b *Binance) exportFundingRates(ctx context.Context, acc telegraf.Accumulator, p *pair, start, end time.Time) (int, error) {
+	params := p.query()
+	params.Set("limit", strconv.Itoa(fundingRateLimit))
+	params.Set("endTime", strconv.FormatInt(end.UnixMilli(), 10))
+
+	var count int
+	for cursor := start; !cursor.After(end); {
+		params.Set("startTime", strconv.FormatInt(cursor.UnixMilli(), 10))
+		if err := b.budget.wait(ctx, fundingRateWeight); err != nil {
+			return count, fmt.Errorf("waiting for rate-limit budget failed: %w", err)
+		}
+
+		var page []fundingRate
+		if err := b.fetchWithTimeout(ctx, withParams(b.apiURL+fundingRateEndpoint, params), &page); err != nil {
+			return count, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		if err := b.addSettledFundingRates(acc, p, page); err != nil {
+			return count, err
+		}
+		count += len(page)
+
+		if len(page) < fundingRateLimit {
+			break
+		}
+		cursor = time.UnixMilli(page[len(page)-1].FundingTime + 1)
+	}
+	return count, nil
+}
+
+func (b *Binance) fetchFundingRates(params url.Values) ([]fundingRate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var rates []fundingRate
+	if err := b.query(ctx, fundingRateEndpoint, params, fundingRateWeight, &rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
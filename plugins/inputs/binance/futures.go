@@ -0,0 +1,100 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Markets the prices are collected from
+const (
+	marketSpot         string = "spot"
+	marketUSDMFutures  string = "usdm_futures"
+	marketCOINMFutures string = "coinm_futures"
+)
+
+const coinmFuturesURLString string = "https://dapi.binance.com/dapi/v1"
+
+const (
+	premiumIndexEndpoint  string = "/premiumIndex"
+	premiumIndexWeight    int64  = 1
+	premiumIndexAllWeight int64  = 10
+)
+
+// premiumIndex is the mark price and funding information of a futures symbol
+type premiumIndex struct {
+	Symbol          string `json:"symbol"`
+	MarkPrice       string `json:"markPrice"`
+	IndexPrice      string `json:"indexPrice"`
+	LastFundingRate string `json:"lastFundingRate"`
+	InterestRate    string `json:"interestRate"`
+	NextFundingTime int64  `json:"nextFundingTime"`
+}
+
+// futures checks if the plugin collects a futures market
+func (b *Binance) futures() bool {
+	return b.Market == marketUSDMFutures || b.Market == marketCOINMFutures
+}
+
+// spotOnlyOptions returns the names of the enabled options relying on
+// endpoints only available on the spot market
+func (b *Binance) spotOnlyOptions() []string {
+	var options []string
+	for name, enabled := range map[string]bool{
+		"avg_price":        b.AvgPrice,
+		"price_bands":      b.PriceBands,
+		"rolling_windows":  len(b.RollingWindows) > 0,
+		"trading_day":      b.TradingDay,
+		"account_balances": b.AccountBalances,
+		"open_orders":      b.OpenOrders,
+		"my_trades":        b.MyTrades,
+		"commission_rates": b.CommissionRates,
+	} {
+		if enabled {
+			options = append(options, name)
+		}
+	}
+	slices.Sort(options)
+	return options
+}
+
+// premiumIndices queries the mark price and funding information of the
+// futures pairs, in a single request for all symbols if collecting more than
+// one pair. The COIN-M futures API returns a list even for a single symbol,
+// so all symbols are queried there.
+func (b *Binance) premiumIndices(ctx context.Context) (map[string]premiumIndex, error) {
+	var indices []premiumIndex
+	if len(b.pairs) == 1 && b.Market == marketUSDMFutures {
+		var index premiumIndex
+		if err := b.query(ctx, premiumIndexEndpoint, b.pairs[0].query(), premiumIndexWeight, &index); err != nil {
+			return nil, err
+		}
+		indices = append(indices, index)
+	} else if err := b.query(ctx, premiumIndexEndpoint, nil, premiumIndexAllWeight, &indices); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]premiumIndex, len(indices))
+	for _, index := range indices {
+		result[index.Symbol] = index
+	}
+	return result, nil
+}
+
+// addPremiumIndex adds the mark price and the index price of the pair's
+// contract to the fields
+func addPremiumIndex(fields map[string]interface{}, p *pair, index premiumIndex) error {
+	prices := make(map[string]interface{}, 2)
+	err := parseFloatFields(prices, map[string]string{
+		"mark_price":  index.MarkPrice,
+		"index_price": index.IndexPrice,
+	})
+	if err != nil {
+		return fmt.Errorf("parsing premium index of symbol %s failed: %w", p.symbol, err)
+	}
+	for name, value := range prices {
+		price, _ := value.(float64)
+		fields[name] = p.price(price)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// fillGap emits the close prices of the one-minute klines between the
+// last emitted price and now if the time in between exceeds the configured
+// threshold.
+func (b *Binance) fillGap(acc telegraf.Accumulator, p *pair, now time.Time) error {
+	last, found := b.state.LastPrice[p.symbol]
+	if !found || now.Sub(last) <= time.Duration(b.GapFillThreshold) {
+		return nil
+	}
+
+	start := last
+	if oldest := now.Add(-time.Duration(b.GapFillMaxAge)); start.Before(oldest) {
+		start = oldest
+	}
+	b.Log.Debugf("Filling gap of %s from %s to %s", p.symbol, start.Format(time.RFC3339), now.Format(time.RFC3339))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var count int
+	err := b.fetchKlines(ctx, p, "1m", start, now, func(klines []kline) {
+		for _, k := range klines {
+			// Use the close price at the end of the kline but skip klines
+			// not closed yet or ending before the last emitted price
+			ts := k.openTime.Add(time.Minute)
+			if !ts.After(start) || !ts.Before(now) {
+				continue
+			}
+			price, ok := k.fields["close"].(float64)
+			if !ok {
+				continue
+			}
+			acc.AddFields("binance", map[string]interface{}{"price": p.price(price)}, p.priceTags, ts)
+			count++
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("filling gap of %s since %s failed: %w", p.symbol, last.Format(time.RFC3339), err)
+	}
+	b.Log.Debugf("Filled gap of %s with %d prices", p.symbol, count)
+	return nil
+}
@@ -0,0 +1,84 @@
+package binance
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// requestTiming records the phases of a single HTTP request
+type requestTiming struct {
+	host       string
+	endpoint   string
+	start      time.Time
+	dnsStart   time.Time
+	dnsDone    time.Time
+	connStart  time.Time
+	connDone   time.Time
+	tlsStart   time.Time
+	tlsDone    time.Time
+	firstByte  time.Time
+	done       time.Time
+	reused     bool
+	statusCode int
+}
+
+// traceRequest returns the request instrumented to record the timing of its
+// phases into the returned record.
+func traceRequest(r *http.Request) (*http.Request, *requestTiming) {
+	timing := &requestTiming{
+		host:     r.URL.Host,
+		endpoint: r.URL.Path,
+		start:    time.Now(),
+	}
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { timing.dnsStart = time.Now() },
+		DNSDone:           func(httptrace.DNSDoneInfo) { timing.dnsDone = time.Now() },
+		ConnectStart:      func(string, string) { timing.connStart = time.Now() },
+		ConnectDone:       func(string, string, error) { timing.connDone = time.Now() },
+		TLSHandshakeStart: func() { timing.tlsStart = time.Now() },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { timing.tlsDone = time.Now() },
+		GotConn:           func(info httptrace.GotConnInfo) { timing.reused = info.Reused },
+		GotFirstResponseByte: func() {
+			timing.firstByte = time.Now()
+		},
+	}
+	return r.WithContext(httptrace.WithClientTrace(r.Context(), trace)), timing
+}
+
+// addHTTPTimings emits the timings of the requests sent since the last call
+func (b *Binance) addHTTPTimings(acc telegraf.Accumulator) {
+	for _, t := range b.timings {
+		fields := map[string]interface{}{
+			"reused":        t.reused,
+			"response_time": t.done.Sub(t.start).Seconds(),
+		}
+		// Phases are only recorded if they happened, e.g. a reused connection
+		// does not involve a DNS lookup, connect or TLS handshake.
+		if !t.dnsStart.IsZero() && !t.dnsDone.IsZero() {
+			fields["dns_lookup_time"] = t.dnsDone.Sub(t.dnsStart).Seconds()
+		}
+		if !t.connStart.IsZero() && !t.connDone.IsZero() {
+			fields["connect_time"] = t.connDone.Sub(t.connStart).Seconds()
+		}
+		if !t.tlsStart.IsZero() && !t.tlsDone.IsZero() {
+			fields["tls_handshake_time"] = t.tlsDone.Sub(t.tlsStart).Seconds()
+		}
+		if !t.firstByte.IsZero() {
+			fields["first_byte_time"] = t.firstByte.Sub(t.start).Seconds()
+		}
+		tags := map[string]string{
+			"host":     t.host,
+			"endpoint": t.endpoint,
+		}
+		if t.statusCode != 0 {
+			tags["status_code"] = strconv.Itoa(t.statusCode)
+		}
+		acc.AddFields("binance_http_timing", fields, tags, t.start)
+	}
+	b.timings = b.timings[:0]
+}
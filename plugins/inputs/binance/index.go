@@ -0,0 +1,87 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	indexInfoEndpoint string = "/indexInfo"
+	indexInfoWeight   int64  = 1
+)
+
+type indexInfo struct {
+	Symbol        string `json:"symbol"`
+	Time          int64  `json:"time"`
+	BaseAssetList []struct {
+		BaseAsset          string `json:"baseAsset"`
+		QuoteAsset         string `json:"quoteAsset"`
+		WeightInQuantity   string `json:"weightInQuantity"`
+		WeightInPercentage string `json:"weightInPercentage"`
+	} `json:"baseAssetList"`
+}
+
+// gatherIndexInfo emits the components of the configured composite indices
+// of the futures market and whether the composition changed since the last
+// gather cycle.
+func (b *Binance) gatherIndexInfo(acc telegraf.Accumulator) {
+	for _, symbol := range b.IndexInfo {
+		acc.AddError(b.gatherIndex(acc, symbol))
+	}
+}
+
+func (b *Binance) gatherIndex(acc telegraf.Accumulator, symbol string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var info indexInfo
+	params := url.Values{"symbol": {symbol}}
+	if err := b.queryFutures(ctx, indexInfoEndpoint, params, indexInfoWeight, &info); err != nil {
+		return err
+	}
+	ts := time.UnixMilli(info.Time)
+
+	components := make([]string, 0, len(info.BaseAssetList))
+	for _, c := range info.BaseAssetList {
+		quantity, err := strconv.ParseFloat(c.WeightInQuantity, 64)
+		if err != nil {
+			return fmt.Errorf("cannot parse quantity weight %q of %s in %s: %w", c.WeightInQuantity, c.BaseAsset, symbol, err)
+		}
+		percentage, err := strconv.ParseFloat(c.WeightInPercentage, 64)
+		if err != nil {
+			return fmt.Errorf("cannot parse percentage weight %q of %s in %s: %w", c.WeightInPercentage, c.BaseAsset, symbol, err)
+		}
+
+		tags := map[string]string{
+			"index": symbol,
+			"base":  c.BaseAsset,
+			"quote": c.QuoteAsset,
+		}
+		fields := map[string]interface{}{
+			"weight_in_quantity":   quantity,
+			"weight_in_percentage": percentage,
+		}
+		acc.AddFields("binance_index_component", fields, tags, ts)
+		components = append(components, c.BaseAsset+"="+c.WeightInQuantity)
+	}
+
+	// Compare the composition to the previous one to make changes visible
+	sort.Strings(components)
+	composition := strings.Join(components, ",")
+	previous, found := b.indexCompositions[symbol]
+	b.indexCompositions[symbol] = composition
+
+	fields := map[string]interface{}{
+		"components": len(components),
+		"changed":    found && previous != composition,
+	}
+	acc.AddFields("binance_index", fields, map[string]string{"index": symbol}, ts)
+	return nil
+}
@@ -0,0 +1,293 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	klinesEndpoint string = "/klines"
+	klinesWeight   int64  = 2
+	// Maximum number of klines returned per request
+	klinesLimit int = 1000
+)
+
+// Kline intervals supported by Binance with their maximum duration
+var klineIntervals = map[string]time.Duration{
+	"1s": time.Second, "1m": time.Minute, "3m": 3 * time.Minute,
+	"5m": 5 * time.Minute, "15m": 15 * time.Minute, "30m": 30 * time.Minute,
+	"1h": time.Hour, "2h": 2 * time.Hour, "4h": 4 * time.Hour,
+	"6h": 6 * time.Hour, "8h": 8 * time.Hour, "12h": 12 * time.Hour,
+	"1d": 24 * time.Hour, "3d": 3 * 24 * time.Hour, "1w": 7 * 24 * time.Hour,
+	"1M": 31 * 24 * time.Hour,
+}
+
+type kline struct {
+	openTime  time.Time
+	closeTime time.Time
+	fields    map[string]interface{}
+}
+
+// UnmarshalJSON decodes the array representation of a kline, i.e.
+// [openTime, open, high, low, close, volume, closeTime, quoteVolume, trades,
+// takerBuyBaseVolume, takerBuyQuoteVolume, ignore]
+func (k *kline) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) < 11 {
+		return fmt.Errorf("invalid kline with %d elements", len(raw))
+	}
+
+	var openTime, closeTime, trades int64
+	for i, v := range map[int]*int64{0: &openTime, 6: &closeTime, 8: &trades} {
+		if err := json.Unmarshal(raw[i], v); err != nil {
+			return fmt.Errorf("invalid kline element %d: %w", i, err)
+		}
+	}
+	k.openTime = time.UnixMilli(openTime)
+	k.closeTime = time.UnixMilli(closeTime)
+	k.fields = map[string]interface{}{"trades": trades}
+
+	names := map[int]string{
+		1:  "open",
+		2:  "high",
+		3:  "low",
+		4:  "close",
+		5:  "volume",
+		7:  "quote_volume",
+		9:  "taker_buy_volume",
+		10: "taker_buy_quote_volume",
+	}
+	for i, name := range names {
+		var s string
+		if err := json.Unmarshal(raw[i], &s); err != nil {
+			return fmt.Errorf("invalid kline element %d: %w", i, err)
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("cannot parse kline %s %q: %w", name, s, err)
+		}
+		k.fields[name] = v
+	}
+	return nil
+}
+
+// fetchKlines pages through all klines of the given interval opened within
+// [start, end], waiting for the rate-limit budget if necessary, and calls fn
+// for each page.
+func (b *Binance) fetchKlines(ctx context.Context, p *pair, interval string, start, end time.Time, fn func([]kline)) error {
+	params := p.query()
+	params.Set("interval", interval)
+	params.Set("limit", strconv.Itoa(klinesLimit))
+	params.Set("endTime", strconv.FormatInt(end.UnixMilli(), 10))
+
+	for cursor := start; !cursor.After(end); {
+		params.Set("startTime", strconv.FormatInt(cursor.UnixMilli(), 10))
+		if err := b.budget.wait(ctx, klinesWeight); err != nil {
+			return fmt.Errorf("waiting for rate-limit budget failed: %w", err)
+		}
+
+		var page []kline
+		if err := b.fetchWithTimeout(ctx, withParams(b.apiURL+klinesEndpoint, params), &page); err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			break
+		}
+		fn(page)
+
+		if len(page) < klinesLimit {
+			break
+		}
+		cursor = page[len(page)-1].closeTime.Add(time.Millisecond)
+	}
+	return nil
+}
+
+// fetchWithTimeout performs a single fetch limited by the configured timeout
+func (b *Binance) fetchWithTimeout(ctx context.Context, address string, v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(b.Timeout))
+	defer cancel()
+	return b.fetch(ctx, address, v)
+}
+
+// gatherKlines emits the klines of the configured interval closed since the
+// last gather cycle for all pairs.
+func (b *Binance) gatherKlines(acc telegraf.Accumulator) {
+	for _, p := range b.pairs {
+		acc.AddError(b.gatherPairKlines(acc, p))
+	}
+}
+
+// klineKey returns the key of the last reported kline of the symbol and
+// interval in the state
+func klineKey(symbol, interval string) string {
+	return symbol + "/" + interval
+}
+
+func (b *Binance) gatherPairKlines(acc telegraf.Accumulator, p *pair) error {
+	now := time.Now()
+
+	// Continue after the last reported kline, also across restarts, and start
+	// with the most recently closed kline otherwise
+	key := klineKey(p.symbol, b.KlineInterval)
+	last, found := b.state.LastKline[key]
+	start := last.Add(time.Millisecond)
+	if !found {
+		start = now.Add(-2 * klineIntervals[b.KlineInterval])
+	}
+
+	params := p.query()
+	params.Set("interval", b.KlineInterval)
+	params.Set("limit", strconv.Itoa(klinesLimit))
+	params.Set("startTime", strconv.FormatInt(start.UnixMilli(), 10))
+	params.Set("endTime", strconv.FormatInt(now.UnixMilli(), 10))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+	var page []kline
+	if err := b.query(ctx, klinesEndpoint, params, klinesWeight, &page); err != nil {
+		return err
+	}
+
+	// Only emit closed klines, the open one is emitted in a later cycle
+	closed := make([]kline, 0, len(page))
+	for _, k := range page {
+		if !k.closeTime.After(now) {
+			closed = append(closed, k)
+		}
+	}
+	if !found && len(closed) > 1 {
+		closed = closed[len(closed)-1:]
+	}
+	if len(closed) == 0 {
+		return nil
+	}
+
+	b.addKlines(acc, p, b.KlineInterval, closed)
+	b.state.LastKline[key] = closed[len(closed)-1].closeTime
+	return nil
+}
+
+func (b *Binance) addKlines(acc telegraf.Accumulator, p *pair, interval string, klines []kline) {
+	tags := p.tagsWith("interval", interval)
+	for _, k := range klines {
+		ts := k.openTime
+		if b.KlineTimestamp == "close" {
+			ts = k.closeTime
+		}
+		acc.AddFields("binance_kline", k.fields, tags, ts)
+	}
+}
+
+// export gathers all klines of the configured historical range or the
+// backfill duration for all pairs. On futures markets, the settled funding
+// rates and the open interest statistics are exported as well if collected.
+// The export is only done once per run of the plugin.
+func (b *Binance) export(acc telegraf.Accumulator) {
+	if b.BackfillDuration > 0 {
+		b.exportStart = time.Now().Add(-time.Duration(b.BackfillDuration))
+	}
+	for _, p := range b.pairs {
+		acc.AddError(b.exportPair(acc, p))
+		if b.FundingRates && p.perpetual() {
+			acc.AddError(b.exportPairHistory(acc, p, "funding rates", b.state.LastFunding[p.symbol], b.exportFundingRates))
+		}
+		if b.OpenInterestPeriod != "" {
+			last := b.futuresDataSeen[openInterestHistEndpoint+p.symbol]
+			acc.AddError(b.exportPairHistory(acc, p, "open interest", last, b.exportOpenInterest))
+		}
+	}
+}
+
+// exportPairHistory exports the history of the pair using the given function
+// resuming after the last reported time in milliseconds, if any.
+func (b *Binance) exportPairHistory(
+	acc telegraf.Accumulator,
+	p *pair,
+	kind string,
+	last int64,
+	fn func(context.Context, telegraf.Accumulator, *pair, time.Time, time.Time) (int, error),
+) error {
+	end := b.exportEnd
+	if end.IsZero() {
+		end = time.Now()
+	}
+	start := b.exportStart
+	if last > 0 && !time.UnixMilli(last).Before(start) {
+		start = time.UnixMilli(last + 1)
+	}
+	if start.After(end) {
+		b.Log.Infof("Skipping export of %s of %s already reported until %s", kind, p.symbol, start.Format(time.RFC3339))
+		return nil
+	}
+	b.Log.Infof("Exporting %s of %s from %s to %s", kind, p.symbol, start.Format(time.RFC3339), end.Format(time.RFC3339))
+
+	count, err := fn(context.Background(), acc, p, start, end)
+	if err != nil {
+		return fmt.Errorf("historical export of %s of %s failed after %d records: %w", kind, p.symbol, count, err)
+	}
+	b.Log.Infof("Historical export of %s of %s completed with %d records", kind, p.symbol, count)
+	return nil
+}
+
+func (b *Binance) exportPair(acc telegraf.Accumulator, p *pair) error {
+	end := b.exportEnd
+	if end.IsZero() {
+		end = time.Now()
+	}
+	// Resume after the klines reported before a restart instead of emitting
+	// them again
+	key := klineKey(p.symbol, b.ExportInterval)
+	start := b.exportStart
+	if last, found := b.state.LastKline[key]; found && !last.Before(start) {
+		start = last.Add(time.Millisecond)
+	}
+	if start.After(end) {
+		b.Log.Infof("Skipping export of %s klines of %s already reported until %s",
+			b.ExportInterval, p.symbol, start.Format(time.RFC3339))
+		return nil
+	}
+	b.Log.Infof("Exporting %s klines of %s from %s to %s",
+		b.ExportInterval, p.symbol, start.Format(time.RFC3339), end.Format(time.RFC3339))
+
+	// Only export klines already closed to get reproducible results
+	now := time.Now()
+	var count int
+	err := b.fetchKlines(context.Background(), p, b.ExportInterval, start, end, func(klines []kline) {
+		for len(klines) > 0 && klines[len(klines)-1].closeTime.After(now) {
+			klines = klines[:len(klines)-1]
+		}
+		b.addKlines(acc, p, b.ExportInterval, klines)
+		count += len(klines)
+
+		// Continue collecting klines of the same interval after the exported
+		// ones
+		if len(klines) > 0 {
+			b.state.LastKline[key] = klines[len(klines)-1].closeTime
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("historical export of %s failed after %d klines: %w", p.symbol, count, err)
+	}
+	b.Log.Infof("Historical export of %s completed with %d klines", p.symbol, count)
+	return nil
+}
+
+func parseExportTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+	return t, nil
+}
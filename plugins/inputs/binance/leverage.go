@@ -0,0 +1,83 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	leverageBracketEndpoint string = "/leverageBracket"
+	leverageBracketWeight   int64  = 1
+)
+
+type leverageBrackets struct {
+	Symbol   string `json:"symbol"`
+	Brackets []struct {
+		Bracket          int     `json:"bracket"`
+		InitialLeverage  int     `json:"initialLeverage"`
+		NotionalCap      float64 `json:"notionalCap"`
+		NotionalFloor    float64 `json:"notionalFloor"`
+		MaintMarginRatio float64 `json:"maintMarginRatio"`
+		Cum              float64 `json:"cum"`
+	} `json:"brackets"`
+}
+
+// gatherLeverageBrackets emits the notional brackets of the configured
+// USDⓈ-M futures symbols with their leverage and maintenance margin and
+// whether the brackets changed since the last gather cycle.
+func (b *Binance) gatherLeverageBrackets(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	// Query the brackets of all symbols at once as the response for a single
+	// symbol differs in structure
+	var all []leverageBrackets
+	if err := b.querySigned(ctx, b.futuresURL+leverageBracketEndpoint, url.Values{}, leverageBracketWeight, &all); err != nil {
+		return err
+	}
+	bySymbol := make(map[string]leverageBrackets, len(all))
+	for _, l := range all {
+		bySymbol[l.Symbol] = l
+	}
+
+	now := time.Now()
+	for _, symbol := range b.LeverageBrackets {
+		l, found := bySymbol[symbol]
+		if !found {
+			acc.AddError(fmt.Errorf("no leverage brackets received for symbol %s", symbol))
+			continue
+		}
+
+		for _, bracket := range l.Brackets {
+			tags := map[string]string{
+				"symbol":  symbol,
+				"bracket": strconv.Itoa(bracket.Bracket),
+			}
+			fields := map[string]interface{}{
+				"initial_leverage":   bracket.InitialLeverage,
+				"notional_cap":       bracket.NotionalCap,
+				"notional_floor":     bracket.NotionalFloor,
+				"maint_margin_ratio": bracket.MaintMarginRatio,
+				"cum":                bracket.Cum,
+			}
+			acc.AddFields("binance_leverage_bracket", fields, tags, now)
+		}
+
+		// Compare the brackets to the previous ones to make changes visible
+		current := fmt.Sprint(l.Brackets)
+		previous, found := b.leverageBracketsSeen[symbol]
+		b.leverageBracketsSeen[symbol] = current
+
+		fields := map[string]interface{}{
+			"brackets": len(l.Brackets),
+			"changed":  found && previous != current,
+		}
+		acc.AddFields("binance_leverage_brackets", fields, map[string]string{"symbol": symbol}, now)
+	}
+	return nil
+}
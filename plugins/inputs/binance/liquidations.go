@@ -0,0 +1,145 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	usdmStreamURLString  string = "wss://fstream.binance.com"
+	coinmStreamURLString string = "wss://dstream.binance.com"
+	// Delay before reconnecting to the stream, e.g. after Binance closed the
+	// connection after 24 hours
+	streamReconnectDelay = 5 * time.Second
+)
+
+// liquidationEvent is a message of the combined liquidation order streams
+type liquidationEvent struct {
+	Stream string `json:"stream"`
+	Data   struct {
+		Order struct {
+			Symbol         string `json:"s"`
+			Side           string `json:"S"`
+			Quantity       string `json:"q"`
+			Price          string `json:"p"`
+			AvgPrice       string `json:"ap"`
+			Status         string `json:"X"`
+			FilledQuantity string `json:"z"`
+			TradeTime      int64  `json:"T"`
+		} `json:"o"`
+	} `json:"data"`
+}
+
+// Start subscribes to the liquidation orders of the pairs if enabled. The
+// streams of pairs added by a later refresh of the selection are not
+// subscribed.
+func (b *Binance) Start(acc telegraf.Accumulator) error {
+	if !b.Liquidations {
+		return nil
+	}
+
+	streams := make([]string, 0, len(b.pairs))
+	tags := make(map[string]map[string]string, len(b.pairs))
+	for _, p := range b.pairs {
+		streams = append(streams, strings.ToLower(p.symbol)+"@forceOrder")
+		tags[p.symbol] = p.tags
+	}
+	address := b.streamURL + "/stream?streams=" + strings.Join(streams, "/")
+
+	transport, err := b.newTransport()
+	if err != nil {
+		return err
+	}
+	dialer := &websocket.Dialer{
+		Proxy:            transport.Proxy,
+		NetDialContext:   transport.DialContext,
+		HandshakeTimeout: time.Duration(b.Timeout),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancelStream = cancel
+	b.streamDone = make(chan struct{})
+	go func() {
+		defer close(b.streamDone)
+		b.streamLiquidations(ctx, acc, dialer, address, tags)
+	}()
+	return nil
+}
+
+// stopLiquidations closes the liquidation stream if subscribed
+func (b *Binance) stopLiquidations() {
+	if b.cancelStream == nil {
+		return
+	}
+	b.cancelStream()
+	<-b.streamDone
+	b.cancelStream = nil
+}
+
+// streamLiquidations emits the liquidation orders received from the stream
+// until the context is cancelled, reconnecting after errors.
+func (b *Binance) streamLiquidations(ctx context.Context, acc telegraf.Accumulator, dialer *websocket.Dialer, address string, tags map[string]map[string]string) {
+	for {
+		conn, resp, err := dialer.DialContext(ctx, address, nil)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if err == nil {
+			b.Log.Debugf("Subscribed to the liquidation orders of %d symbols", len(tags))
+			// Unblock reading when stopped
+			stop := context.AfterFunc(ctx, func() { conn.Close() })
+			for {
+				var event liquidationEvent
+				if err = conn.ReadJSON(&event); err != nil {
+					break
+				}
+				acc.AddError(addLiquidation(acc, event, tags))
+			}
+			stop()
+			conn.Close()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		acc.AddError(fmt.Errorf("liquidation stream failed, reconnecting: %w", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(streamReconnectDelay):
+		}
+	}
+}
+
+// addLiquidation emits the liquidation order of the event with the time of
+// its last trade
+func addLiquidation(acc telegraf.Accumulator, event liquidationEvent, tags map[string]map[string]string) error {
+	o := event.Data.Order
+	pairTags, found := tags[o.Symbol]
+	if !found {
+		return fmt.Errorf("received liquidation order of unknown symbol %q on stream %q", o.Symbol, event.Stream)
+	}
+	fields := map[string]interface{}{"status": o.Status}
+	err := parseFloatFields(fields, map[string]string{
+		"price":           o.Price,
+		"avg_price":       o.AvgPrice,
+		"quantity":        o.Quantity,
+		"filled_quantity": o.FilledQuantity,
+	})
+	if err != nil {
+		return fmt.Errorf("parsing liquidation order of %s failed: %w", o.Symbol, err)
+	}
+	orderTags := make(map[string]string, len(pairTags)+1)
+	for k, v := range pairTags {
+		orderTags[k] = v
+	}
+	orderTags["side"] = strings.ToLower(o.Side)
+	acc.AddFields("binance_liquidation", fields, orderTags, time.UnixMilli(o.TradeTime))
+	return nil
+}
@@ -0,0 +1,122 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	sapiV2URLString            string = "https://api.binance.com/sapi/v2"
+	loanOngoingOrdersEndpoint  string = "/loan/flexible/ongoing/orders"
+	loanCollateralDataEndpoint string = "/loan/flexible/collateral/data"
+	loanOngoingOrdersWeight    int64  = 300
+	loanCollateralDataWeight   int64  = 400
+	// The LTV thresholds of the collateral assets rarely change
+	loanCollateralRefresh = time.Hour
+	// Maximum number of loans returned per request
+	loanOngoingOrdersLimit int = 100
+)
+
+type loanOngoingOrders struct {
+	Rows []struct {
+		LoanCoin         string `json:"loanCoin"`
+		TotalDebt        string `json:"totalDebt"`
+		CollateralCoin   string `json:"collateralCoin"`
+		CollateralAmount string `json:"collateralAmount"`
+		CurrentLTV       string `json:"currentLTV"`
+	} `json:"rows"`
+	Total int `json:"total"`
+}
+
+type loanCollateralData struct {
+	Rows []struct {
+		CollateralCoin string `json:"collateralCoin"`
+		InitialLTV     string `json:"initialLTV"`
+		MarginCallLTV  string `json:"marginCallLTV"`
+		LiquidationLTV string `json:"liquidationLTV"`
+	} `json:"rows"`
+}
+
+// gatherCryptoLoans emits the debt, collateral and loan-to-value ratio (LTV)
+// of the ongoing flexible-rate crypto loans of the account together with the
+// LTV thresholds of the collateral asset.
+func (b *Binance) gatherCryptoLoans(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	if err := b.refreshLoanCollateral(ctx); err != nil {
+		// Continue with the previous thresholds if available
+		acc.AddError(err)
+	}
+
+	params := url.Values{"limit": {strconv.Itoa(loanOngoingOrdersLimit)}}
+	var orders loanOngoingOrders
+	if err := b.querySigned(ctx, b.sapiV2URL+loanOngoingOrdersEndpoint, params, loanOngoingOrdersWeight, &orders); err != nil {
+		return err
+	}
+	if orders.Total > len(orders.Rows) {
+		b.Log.Warnf("Reporting only %d of %d crypto loans", len(orders.Rows), orders.Total)
+	}
+
+	for _, o := range orders.Rows {
+		fields := make(map[string]interface{}, 8)
+		err := parseFloatFields(fields, map[string]string{
+			"total_debt":        o.TotalDebt,
+			"collateral_amount": o.CollateralAmount,
+			"current_ltv":       o.CurrentLTV,
+		})
+		if err != nil {
+			return fmt.Errorf("parsing crypto loan of %s against %s failed: %w", o.LoanCoin, o.CollateralCoin, err)
+		}
+		// The LTV is the debt per value of the collateral in the loan asset
+		if ltv, _ := fields["current_ltv"].(float64); ltv > 0 {
+			debt, _ := fields["total_debt"].(float64)
+			fields["collateral_value"] = debt / ltv
+		}
+		for name, value := range b.loanCollateral[o.CollateralCoin] {
+			fields[name] = value
+		}
+
+		tags := map[string]string{
+			"loan_coin":       o.LoanCoin,
+			"collateral_coin": o.CollateralCoin,
+		}
+		acc.AddFields("binance_crypto_loan", fields, tags)
+	}
+	return nil
+}
+
+// refreshLoanCollateral queries the LTV thresholds of the collateral assets
+// at most once per refresh interval
+func (b *Binance) refreshLoanCollateral(ctx context.Context) error {
+	if time.Since(b.collateralQueried) < loanCollateralRefresh {
+		return nil
+	}
+
+	var data loanCollateralData
+	if err := b.querySigned(ctx, b.sapiV2URL+loanCollateralDataEndpoint, url.Values{}, loanCollateralDataWeight, &data); err != nil {
+		return fmt.Errorf("querying crypto loan collateral data failed: %w", err)
+	}
+
+	collateral := make(map[string]map[string]interface{}, len(data.Rows))
+	for _, r := range data.Rows {
+		fields := make(map[string]interface{}, 3)
+		err := parseFloatFields(fields, map[string]string{
+			"initial_ltv":     r.InitialLTV,
+			"margin_call_ltv": r.MarginCallLTV,
+			"liquidation_ltv": r.LiquidationLTV,
+		})
+		if err != nil {
+			return fmt.Errorf("parsing crypto loan collateral data of %s failed: %w", r.CollateralCoin, err)
+		}
+		collateral[r.CollateralCoin] = fields
+	}
+	b.loanCollateral = collateral
+	b.collateralQueried = time.Now()
+	return nil
+}
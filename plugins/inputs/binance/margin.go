@@ -0,0 +1,173 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	sapiURLString              string = "https://api.binance.com/sapi/v1"
+	crossMarginDataEndpoint    string = "/margin/crossMarginData"
+	isolatedMarginDataEndpoint string = "/margin/isolatedMarginData"
+	maxBorrowableEndpoint      string = "/margin/maxBorrowable"
+	// Request weight for querying a single asset or symbol
+	marginDataWeight    int64 = 1
+	maxBorrowableWeight int64 = 50
+)
+
+type crossMarginData struct {
+	VipLevel       int    `json:"vipLevel"`
+	Coin           string `json:"coin"`
+	DailyInterest  string `json:"dailyInterest"`
+	YearlyInterest string `json:"yearlyInterest"`
+	BorrowLimit    string `json:"borrowLimit"`
+}
+
+type isolatedMarginData struct {
+	VipLevel int    `json:"vipLevel"`
+	Symbol   string `json:"symbol"`
+	Leverage string `json:"leverage"`
+	Data     []struct {
+		Coin          string `json:"coin"`
+		DailyInterest string `json:"dailyInterest"`
+		BorrowLimit   string `json:"borrowLimit"`
+	} `json:"data"`
+}
+
+type maxBorrowable struct {
+	Amount      string `json:"amount"`
+	BorrowLimit string `json:"borrowLimit"`
+}
+
+// gatherMarginInterest emits the current interest rates for borrowing the
+// configured assets with cross margin and the assets of the pairs with
+// isolated margin.
+func (b *Binance) gatherMarginInterest(acc telegraf.Accumulator) {
+	for _, asset := range b.MarginAssets {
+		acc.AddError(b.gatherCrossMarginInterest(acc, asset))
+	}
+	if b.MarginIsolated {
+		for _, p := range b.pairs {
+			acc.AddError(b.gatherIsolatedMarginInterest(acc, p))
+		}
+	}
+}
+
+func (b *Binance) marginParams() url.Values {
+	params := url.Values{}
+	if b.MarginVipLevel >= 0 {
+		params.Set("vipLevel", strconv.Itoa(b.MarginVipLevel))
+	}
+	return params
+}
+
+func (b *Binance) gatherCrossMarginInterest(acc telegraf.Accumulator, asset string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	params := b.marginParams()
+	params.Set("coin", asset)
+	var data []crossMarginData
+	if err := b.querySigned(ctx, b.sapiURL+crossMarginDataEndpoint, params, marginDataWeight, &data); err != nil {
+		return err
+	}
+
+	for _, d := range data {
+		fields, err := interestFields(d.DailyInterest, d.BorrowLimit)
+		if err == nil {
+			err = parseFloatFields(fields, map[string]string{"yearly_interest_rate": d.YearlyInterest})
+		}
+		if err != nil {
+			return fmt.Errorf("parsing cross-margin data of %s failed: %w", d.Coin, err)
+		}
+		if b.MarginMaxBorrowable {
+			if err := b.addMaxBorrowable(ctx, fields, d.Coin, ""); err != nil {
+				return err
+			}
+		}
+
+		tags := map[string]string{
+			"mode":      "cross",
+			"asset":     d.Coin,
+			"vip_level": strconv.Itoa(d.VipLevel),
+		}
+		acc.AddFields("binance_margin_interest", fields, tags)
+	}
+	return nil
+}
+
+func (b *Binance) gatherIsolatedMarginInterest(acc telegraf.Accumulator, p *pair) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	params := b.marginParams()
+	params.Set("symbol", p.symbol)
+	var data []isolatedMarginData
+	if err := b.querySigned(ctx, b.sapiURL+isolatedMarginDataEndpoint, params, marginDataWeight, &data); err != nil {
+		return err
+	}
+
+	for _, d := range data {
+		for _, c := range d.Data {
+			fields, err := interestFields(c.DailyInterest, c.BorrowLimit)
+			if err != nil {
+				return fmt.Errorf("parsing isolated-margin data of %s in %s failed: %w", c.Coin, d.Symbol, err)
+			}
+			if b.MarginMaxBorrowable {
+				if err := b.addMaxBorrowable(ctx, fields, c.Coin, p.symbol); err != nil {
+					return err
+				}
+			}
+
+			tags := p.tagsWith("mode", "isolated")
+			tags["asset"] = c.Coin
+			tags["vip_level"] = strconv.Itoa(d.VipLevel)
+			acc.AddFields("binance_margin_interest", fields, tags)
+		}
+	}
+	return nil
+}
+
+// addMaxBorrowable adds the amount of the asset the account can currently
+// borrow and the account's borrow limit of the asset to the fields, for the
+// given isolated-margin symbol or with cross margin if empty.
+func (b *Binance) addMaxBorrowable(ctx context.Context, fields map[string]interface{}, asset, isolatedSymbol string) error {
+	params := url.Values{"asset": {asset}}
+	if isolatedSymbol != "" {
+		params.Set("isolatedSymbol", isolatedSymbol)
+	}
+	var borrowable maxBorrowable
+	if err := b.querySigned(ctx, b.sapiURL+maxBorrowableEndpoint, params, maxBorrowableWeight, &borrowable); err != nil {
+		return err
+	}
+	err := parseFloatFields(fields, map[string]string{
+		"max_borrowable":       borrowable.Amount,
+		"account_borrow_limit": borrowable.BorrowLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("parsing maximum borrowable amount of %s failed: %w", asset, err)
+	}
+	return nil
+}
+
+// interestFields returns the daily interest rate, the hourly rate charged by
+// Binance derived from it and the borrow limit
+func interestFields(daily, borrowLimit string) (map[string]interface{}, error) {
+	rate, err := strconv.ParseFloat(daily, 64)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse daily interest %q: %w", daily, err)
+	}
+	fields := map[string]interface{}{
+		"daily_interest_rate":  rate,
+		"hourly_interest_rate": rate / 24,
+	}
+	if err := parseFloatFields(fields, map[string]string{"borrow_limit": borrowLimit}); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
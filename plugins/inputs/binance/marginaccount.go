@@ -0,0 +1,156 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	marginAccountEndpoint         string = "/margin/account"
+	isolatedMarginAccountEndpoint string = "/margin/isolated/account"
+	// Request weight of both the cross-margin and isolated-margin account
+	marginAccountWeight int64 = 10
+)
+
+type marginAsset struct {
+	Asset    string `json:"asset"`
+	Free     string `json:"free"`
+	Locked   string `json:"locked"`
+	Borrowed string `json:"borrowed"`
+	Interest string `json:"interest"`
+	NetAsset string `json:"netAsset"`
+}
+
+func (a marginAsset) fields() (map[string]interface{}, error) {
+	fields := make(map[string]interface{}, 5)
+	err := parseFloatFields(fields, map[string]string{
+		"free":      a.Free,
+		"locked":    a.Locked,
+		"borrowed":  a.Borrowed,
+		"interest":  a.Interest,
+		"net_asset": a.NetAsset,
+	})
+	return fields, err
+}
+
+type marginAccount struct {
+	MarginLevel         string        `json:"marginLevel"`
+	TotalAssetOfBtc     string        `json:"totalAssetOfBtc"`
+	TotalLiabilityOfBtc string        `json:"totalLiabilityOfBtc"`
+	TotalNetAssetOfBtc  string        `json:"totalNetAssetOfBtc"`
+	BorrowEnabled       bool          `json:"borrowEnabled"`
+	TradeEnabled        bool          `json:"tradeEnabled"`
+	UserAssets          []marginAsset `json:"userAssets"`
+}
+
+type isolatedMarginAccount struct {
+	Assets []struct {
+		Symbol            string      `json:"symbol"`
+		BaseAsset         marginAsset `json:"baseAsset"`
+		QuoteAsset        marginAsset `json:"quoteAsset"`
+		Enabled           bool        `json:"enabled"`
+		TradeEnabled      bool        `json:"tradeEnabled"`
+		MarginLevel       string      `json:"marginLevel"`
+		MarginLevelStatus string      `json:"marginLevelStatus"`
+		MarginRatio       string      `json:"marginRatio"`
+		IndexPrice        string      `json:"indexPrice"`
+		LiquidatePrice    string      `json:"liquidatePrice"`
+		LiquidateRate     string      `json:"liquidateRate"`
+	} `json:"assets"`
+}
+
+// gatherMarginAccount emits the margin level and totals of the cross-margin
+// account and the holdings, loans and interest of each asset in it.
+func (b *Binance) gatherMarginAccount(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var account marginAccount
+	if err := b.querySigned(ctx, b.sapiURL+marginAccountEndpoint, url.Values{}, marginAccountWeight, &account); err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{
+		"borrow_enabled": account.BorrowEnabled,
+		"trade_enabled":  account.TradeEnabled,
+	}
+	err := parseFloatFields(fields, map[string]string{
+		"margin_level":        account.MarginLevel,
+		"total_asset_btc":     account.TotalAssetOfBtc,
+		"total_liability_btc": account.TotalLiabilityOfBtc,
+		"total_net_asset_btc": account.TotalNetAssetOfBtc,
+	})
+	if err != nil {
+		return fmt.Errorf("parsing cross-margin account failed: %w", err)
+	}
+	acc.AddFields("binance_margin_account", fields, map[string]string{"mode": "cross"})
+
+	for _, a := range account.UserAssets {
+		fields, err := a.fields()
+		if err != nil {
+			return fmt.Errorf("parsing cross-margin asset %s failed: %w", a.Asset, err)
+		}
+		// The account lists all assets available for margin trading
+		if fields["free"] == 0.0 && fields["locked"] == 0.0 && fields["borrowed"] == 0.0 && fields["interest"] == 0.0 {
+			continue
+		}
+		acc.AddFields("binance_margin_asset", fields, map[string]string{"mode": "cross", "asset": a.Asset})
+	}
+	return nil
+}
+
+// gatherIsolatedMarginAccount emits the margin level, margin ratio and
+// liquidation price of each isolated-margin pair of the account and the
+// holdings, loans and interest of both assets of the pair.
+func (b *Binance) gatherIsolatedMarginAccount(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var account isolatedMarginAccount
+	if err := b.querySigned(ctx, b.sapiURL+isolatedMarginAccountEndpoint, url.Values{}, marginAccountWeight, &account); err != nil {
+		return err
+	}
+
+	for _, a := range account.Assets {
+		fields := map[string]interface{}{
+			"enabled":             a.Enabled,
+			"trade_enabled":       a.TradeEnabled,
+			"margin_level_status": a.MarginLevelStatus,
+		}
+		err := parseFloatFields(fields, map[string]string{
+			"margin_level":      a.MarginLevel,
+			"margin_ratio":      a.MarginRatio,
+			"index_price":       a.IndexPrice,
+			"liquidation_price": a.LiquidatePrice,
+			"liquidation_rate":  a.LiquidateRate,
+		})
+		if err != nil {
+			return fmt.Errorf("parsing isolated-margin account of %s failed: %w", a.Symbol, err)
+		}
+		tags := map[string]string{
+			"mode":  "isolated",
+			"base":  a.BaseAsset.Asset,
+			"quote": a.QuoteAsset.Asset,
+		}
+		acc.AddFields("binance_margin_account", fields, tags)
+
+		for _, asset := range []marginAsset{a.BaseAsset, a.QuoteAsset} {
+			fields, err := asset.fields()
+			if err != nil {
+				return fmt.Errorf("parsing isolated-margin asset %s of %s failed: %w", asset.Asset, a.Symbol, err)
+			}
+			assetTags := map[string]string{
+				"mode":  "isolated",
+				"base":  a.BaseAsset.Asset,
+				"quote": a.QuoteAsset.Asset,
+				"asset": asset.Asset,
+			}
+			acc.AddFields("binance_margin_asset", fields, assetTags)
+		}
+	}
+	return nil
+}
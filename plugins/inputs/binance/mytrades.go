@@ -0,0 +1,179 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	myTradesEndpoint string = "/myTrades"
+	myTradesWeight   int64  = 20
+	// Maximum number of fills returned per request
+	myTradesLimit int = 1000
+)
+
+// fill is a trade of an order of the account
+type fill struct {
+	ID              int64  `json:"id"`
+	OrderID         int64  `json:"orderId"`
+	Price           string `json:"price"`
+	Qty             string `json:"qty"`
+	QuoteQty        string `json:"quoteQty"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+	Time            int64  `json:"time"`
+	IsBuyer         bool   `json:"isBuyer"`
+	IsMaker         bool   `json:"isMaker"`
+}
+
+// fillSummary aggregates the fills of a pair within a gather cycle
+type fillSummary struct {
+	fills        int
+	makerFills   int
+	buyQuantity  float64
+	buyQuote     float64
+	sellQuantity float64
+	sellQuote    float64
+	commissions  map[string]float64
+	firstID      int64
+	lastID       int64
+}
+
+func (s *fillSummary) add(f fill, fields map[string]interface{}) {
+	if s.fills == 0 {
+		s.firstID = f.ID
+	}
+	s.fills++
+	s.lastID = f.ID
+	if f.IsMaker {
+		s.makerFills++
+	}
+	quantity, _ := fields["quantity"].(float64)
+	quote, _ := fields["quote_quantity"].(float64)
+	if f.IsBuyer {
+		s.buyQuantity += quantity
+		s.buyQuote += quote
+	} else {
+		s.sellQuantity += quantity
+		s.sellQuote += quote
+	}
+	if s.commissions == nil {
+		s.commissions = make(map[string]float64)
+	}
+	commission, _ := fields["commission"].(float64)
+	s.commissions[f.CommissionAsset] += commission
+}
+
+func (s *fillSummary) fields() map[string]interface{} {
+	fields := map[string]interface{}{"fills": s.fills}
+	if s.fills == 0 {
+		return fields
+	}
+	fields["maker_fills"] = s.makerFills
+	fields["buy_quantity"] = s.buyQuantity
+	fields["buy_quote_quantity"] = s.buyQuote
+	fields["sell_quantity"] = s.sellQuantity
+	fields["sell_quote_quantity"] = s.sellQuote
+	if s.buyQuantity > 0 {
+		fields["buy_avg_price"] = s.buyQuote / s.buyQuantity
+	}
+	if s.sellQuantity > 0 {
+		fields["sell_avg_price"] = s.sellQuote / s.sellQuantity
+	}
+	for asset, commission := range s.commissions {
+		fields["commission_"+strings.ToLower(asset)] = commission
+	}
+	fields["first_fill_id"] = s.firstID
+	fields["last_fill_id"] = s.lastID
+	return fields
+}
+
+// gatherMyTrades emits the fills of the account's orders of the pairs since
+// the last reported one, either one metric per fill or a summary per pair.
+func (b *Binance) gatherMyTrades(acc telegraf.Accumulator) {
+	for _, p := range b.pairs {
+		acc.AddError(b.gatherPairMyTrades(acc, p))
+	}
+}
+
+func (b *Binance) gatherPairMyTrades(acc telegraf.Accumulator, p *pair) error {
+	params := p.query()
+	params.Set("limit", strconv.Itoa(myTradesLimit))
+
+	var summary fillSummary
+	for {
+		// Without a known last fill, start with the most recent ones
+		if id, found := b.state.LastMyTradeID[p.symbol]; found {
+			params.Set("fromId", strconv.FormatInt(id+1, 10))
+		}
+
+		page, err := b.fetchMyTrades(params)
+		if errors.Is(err, errBudgetExhausted) {
+			b.Log.Debugf("Continuing fills of %s in the next gather cycle: %v", p.symbol, err)
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, f := range page {
+			fields := map[string]interface{}{
+				"id":               f.ID,
+				"order_id":         f.OrderID,
+				"commission_asset": f.CommissionAsset,
+			}
+			err := parseFloatFields(fields, map[string]string{
+				"price":          f.Price,
+				"quantity":       f.Qty,
+				"quote_quantity": f.QuoteQty,
+				"commission":     f.Commission,
+			})
+			if err != nil {
+				return fmt.Errorf("parsing fill %d of %s failed: %w", f.ID, p.symbol, err)
+			}
+
+			if b.MyTradesSummary {
+				summary.add(f, fields)
+			} else {
+				side, role := "sell", "taker"
+				if f.IsBuyer {
+					side = "buy"
+				}
+				if f.IsMaker {
+					role = "maker"
+				}
+				tags := p.tagsWith("side", side)
+				tags["role"] = role
+				acc.AddFields("binance_fill", fields, tags, time.UnixMilli(f.Time))
+			}
+			b.state.LastMyTradeID[p.symbol] = f.ID
+		}
+
+		if len(page) < myTradesLimit {
+			break
+		}
+	}
+
+	if b.MyTradesSummary {
+		acc.AddFields("binance_fills", summary.fields(), p.tags)
+	}
+	return nil
+}
+
+func (b *Binance) fetchMyTrades(params url.Values) ([]fill, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var fills []fill
+	if err := b.querySigned(ctx, b.apiURL+myTradesEndpoint, params, myTradesWeight, &fills); err != nil {
+		return nil, err
+	}
+	return fills, nil
+}
@@ -0,0 +1,170 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	// Statistics of the USDⓈ-M futures market are served outside of the API
+	futuresDataURLString string = "https://fapi.binance.com/futures/data"
+
+	openInterestEndpoint     string = "/openInterest"
+	openInterestWeight       int64  = 1
+	openInterestHistEndpoint string = "/openInterestHist"
+	openInterestHistWeight   int64  = 1
+	// Maximum number of periods of statistics returned per request
+	futuresDataLimit int = 500
+)
+
+// Periods of the futures market statistics supported by Binance
+var futuresDataPeriods = []string{"5m", "15m", "30m", "1h", "2h", "4h", "6h", "12h", "1d"}
+
+// futuresDataQuery returns the parameters for querying the statistics of the
+// pair from the given endpoint for the given period. Without a known last
+// period, only the most recent one is queried.
+func (b *Binance) futuresDataQuery(endpoint string, p *pair, period string) url.Values {
+	params := p.query()
+	params.Set("period", period)
+	if last, found := b.futuresDataSeen[endpoint+p.symbol]; found {
+		params.Set("startTime", strconv.FormatInt(last+1, 10))
+		params.Set("limit", strconv.Itoa(futuresDataLimit))
+	} else {
+		params.Set("limit", "1")
+	}
+	return params
+}
+
+type openInterest struct {
+	Symbol       string `json:"symbol"`
+	OpenInterest string `json:"openInterest"`
+	Time         int64  `json:"time"`
+}
+
+type openInterestStats struct {
+	Symbol               string `json:"symbol"`
+	SumOpenInterest      string `json:"sumOpenInterest"`
+	SumOpenInterestValue string `json:"sumOpenInterestValue"`
+	Timestamp            int64  `json:"timestamp"`
+}
+
+// gatherOpenInterest emits the current open interest of the contracts and,
+// on the USDⓈ-M futures market, its value at the mark price.
+func (b *Binance) gatherOpenInterest(acc telegraf.Accumulator, indices map[string]premiumIndex) {
+	for _, p := range b.pairs {
+		acc.AddError(b.gatherPairOpenInterest(acc, p, indices[p.symbol]))
+	}
+}
+
+func (b *Binance) gatherPairOpenInterest(acc telegraf.Accumulator, p *pair, index premiumIndex) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var oi openInterest
+	if err := b.query(ctx, openInterestEndpoint, p.query(), openInterestWeight, &oi); err != nil {
+		return err
+	}
+	values := map[string]string{"open_interest": oi.OpenInterest}
+	// The open interest of COIN-M contracts is given in contracts
+	if b.Market == marketUSDMFutures && index.MarkPrice != "" {
+		values["mark_price"] = index.MarkPrice
+	}
+	fields := make(map[string]interface{}, 2)
+	if err := parseFloatFields(fields, values); err != nil {
+		return fmt.Errorf("parsing open interest of %s failed: %w", p.symbol, err)
+	}
+	if mark, found := fields["mark_price"].(float64); found {
+		delete(fields, "mark_price")
+		quantity, _ := fields["open_interest"].(float64)
+		fields["open_interest_value"] = quantity * mark
+	}
+	acc.AddFields("binance_open_interest", fields, p.tags, time.UnixMilli(oi.Time))
+	return nil
+}
+
+// gatherOpenInterestHistory emits the open interest statistics of the
+// contracts for the periods completed since the last reported one.
+func (b *Binance) gatherOpenInterestHistory(acc telegraf.Accumulator) {
+	for _, p := range b.pairs {
+		acc.AddError(b.gatherPairOpenInterestHistory(acc, p))
+	}
+}
+
+func (b *Binance) gatherPairOpenInterestHistory(acc telegraf.Accumulator, p *pair) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	params := b.futuresDataQuery(openInterestHistEndpoint, p, b.OpenInterestPeriod)
+	var stats []openInterestStats
+	if err := b.queryURL(ctx, b.futuresDataURL+openInterestHistEndpoint, params, openInterestHistWeight, &stats); err != nil {
+		return err
+	}
+
+	return b.addOpenInterestStats(acc, p, stats)
+}
+
+// addOpenInterestStats emits the open interest statistics of the pair and
+// remembers the last period as reported. The statistics are only emitted if
+// all of them can be parsed.
+func (b *Binance) addOpenInterestStats(acc telegraf.Accumulator, p *pair, stats []openInterestStats) error {
+	parsed := make([]map[string]interface{}, 0, len(stats))
+	for _, s := range stats {
+		fields := make(map[string]interface{}, 2)
+		err := parseFloatFields(fields, map[string]string{
+			"open_interest":       s.SumOpenInterest,
+			"open_interest_value": s.SumOpenInterestValue,
+		})
+		if err != nil {
+			return fmt.Errorf("parsing open interest statistics of %s failed: %w", p.symbol, err)
+		}
+		parsed = append(parsed, fields)
+	}
+
+	tags := p.tagsWith("period", b.OpenInterestPeriod)
+	for i, s := range stats {
+		acc.AddFields("binance_open_interest", parsed[i], tags, time.UnixMilli(s.Timestamp))
+		b.futuresDataSeen[openInterestHistEndpoint+p.symbol] = s.Timestamp
+	}
+	return nil
+}
+
+// exportOpenInterest emits the open interest statistics of the pair within
+// the given range, paging through the range as required. It returns the
+// number of emitted periods.
+func (b *Binance) exportOpenInterest(ctx context.Context, acc telegraf.Accumulator, p *pair, start, end time.Time) (int, error) {
+	params := p.query()
+	params.Set("period", b.OpenInterestPeriod)
+	params.Set("limit", strconv.Itoa(futuresDataLimit))
+	params.Set("endTime", strconv.FormatInt(end.UnixMilli(), 10))
+
+	var count int
+	for cursor := start; !cursor.After(end); {
+		params.Set("startTime", strconv.FormatInt(cursor.UnixMilli(), 10))
+		if err := b.budget.wait(ctx, openInterestHistWeight); err != nil {
+			return count, fmt.Errorf("waiting for rate-limit budget failed: %w", err)
+		}
+
+		var page []openInterestStats
+		if err := b.fetchWithTimeout(ctx, withParams(b.futuresDataURL+openInterestHistEndpoint, params), &page); err != nil {
+			return count, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		if err := b.addOpenInterestStats(acc, p, page); err != nil {
+			return count, err
+		}
+		count += len(page)
+
+		if len(page) < futuresDataLimit {
+			break
+		}
+		cursor = time.UnixMilli(page[len(page)-1].Timestamp + 1)
+	}
+	return count, nil
+}
@@ -0,0 +1,126 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	openOrdersEndpoint string = "/openOrders"
+	// Request weight for the open orders of a single symbol and all symbols
+	openOrdersWeight    int64 = 6
+	openOrdersAllWeight int64 = 80
+)
+
+type openOrder struct {
+	Symbol      string `json:"symbol"`
+	OrderID     int64  `json:"orderId"`
+	Price       string `json:"price"`
+	StopPrice   string `json:"stopPrice"`
+	OrigQty     string `json:"origQty"`
+	ExecutedQty string `json:"executedQty"`
+	Side        string `json:"side"`
+	Time        int64  `json:"time"`
+}
+
+// orderStats summarizes the open orders of a pair on one side
+type orderStats struct {
+	orders    int
+	remaining float64
+	notional  float64
+	oldest    time.Time
+	ages      time.Duration
+}
+
+func (s *orderStats) fields(now time.Time) map[string]interface{} {
+	fields := map[string]interface{}{
+		"orders":             s.orders,
+		"remaining_quantity": s.remaining,
+		"notional":           s.notional,
+	}
+	if s.orders > 0 {
+		fields["oldest_age"] = now.Sub(s.oldest).Seconds()
+		fields["mean_age"] = s.ages.Seconds() / float64(s.orders)
+	}
+	return fields
+}
+
+func (s *orderStats) add(o openOrder, now time.Time) error {
+	quantity, err := strconv.ParseFloat(o.OrigQty, 64)
+	if err != nil {
+		return fmt.Errorf("cannot parse quantity %q of order %d: %w", o.OrigQty, o.OrderID, err)
+	}
+	executed, err := strconv.ParseFloat(o.ExecutedQty, 64)
+	if err != nil {
+		return fmt.Errorf("cannot parse executed quantity %q of order %d: %w", o.ExecutedQty, o.OrderID, err)
+	}
+	price, err := strconv.ParseFloat(o.Price, 64)
+	if err != nil {
+		return fmt.Errorf("cannot parse price %q of order %d: %w", o.Price, o.OrderID, err)
+	}
+	// Stop-market orders have no limit price, value them at the stop price
+	if price == 0 && o.StopPrice != "" {
+		if price, err = strconv.ParseFloat(o.StopPrice, 64); err != nil {
+			return fmt.Errorf("cannot parse stop price %q of order %d: %w", o.StopPrice, o.OrderID, err)
+		}
+	}
+
+	created := time.UnixMilli(o.Time)
+	s.orders++
+	s.remaining += quantity - executed
+	s.notional += (quantity - executed) * price
+	s.ages += now.Sub(created)
+	if s.oldest.IsZero() || created.Before(s.oldest) {
+		s.oldest = created
+	}
+	return nil
+}
+
+// gatherOpenOrders emits the number, remaining quantity, notional and age of
+// the open orders per pair and side, querying the orders of all symbols at
+// once if cheaper than querying each pair.
+func (b *Binance) gatherOpenOrders(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var orders []openOrder
+	if int64(len(b.pairs))*openOrdersWeight < openOrdersAllWeight {
+		for _, p := range b.pairs {
+			var page []openOrder
+			if err := b.querySigned(ctx, b.apiURL+openOrdersEndpoint, p.query(), openOrdersWeight, &page); err != nil {
+				return err
+			}
+			orders = append(orders, page...)
+		}
+	} else if err := b.querySigned(ctx, b.apiURL+openOrdersEndpoint, url.Values{}, openOrdersAllWeight, &orders); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	stats := make(map[string]map[string]*orderStats, len(b.pairs))
+	for _, p := range b.pairs {
+		stats[p.symbol] = map[string]*orderStats{"BUY": {}, "SELL": {}}
+	}
+	for _, o := range orders {
+		s, found := stats[o.Symbol][o.Side]
+		if !found {
+			continue
+		}
+		if err := s.add(o, now); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range b.pairs {
+		for _, side := range []string{"BUY", "SELL"} {
+			acc.AddFields("binance_open_orders", stats[p.symbol][side].fields(now), p.tagsWith("side", strings.ToLower(side)))
+		}
+	}
+	return nil
+}
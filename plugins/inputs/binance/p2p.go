@@ -0,0 +1,148 @@
+package binance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const p2pURLString string = "https://p2p.binance.com/bapi/c2c/v2/friendly/c2c/adv/search"
+
+// P2P advertisements to collect prices for
+type p2pMarket struct {
+	Asset          string   `toml:"asset"`
+	Fiat           string   `toml:"fiat"`
+	PaymentMethods []string `toml:"payment_methods"`
+	Rows           int      `toml:"rows"`
+}
+
+type p2pRequest struct {
+	Asset     string   `json:"asset"`
+	Fiat      string   `json:"fiat"`
+	TradeType string   `json:"tradeType"`
+	PayTypes  []string `json:"payTypes"`
+	Page      int      `json:"page"`
+	Rows      int      `json:"rows"`
+}
+
+type p2pResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+	Data    []struct {
+		Adv struct {
+			Price            string `json:"price"`
+			TradableQuantity string `json:"tradableQuantity"`
+		} `json:"adv"`
+	} `json:"data"`
+}
+
+func (m *p2pMarket) init() error {
+	if m.Asset == "" || m.Fiat == "" {
+		return errors.New("p2p asset and fiat cannot be empty")
+	}
+	if m.Rows == 0 {
+		m.Rows = 20
+	}
+	if m.Rows < 1 || m.Rows > 20 {
+		return fmt.Errorf("p2p rows %d not between 1 and 20", m.Rows)
+	}
+	m.Asset = strings.ToUpper(m.Asset)
+	m.Fiat = strings.ToUpper(m.Fiat)
+	return nil
+}
+
+// gatherP2P collects the advertised prices for buying and selling the asset
+// in all configured P2P markets.
+func (b *Binance) gatherP2P(acc telegraf.Accumulator) {
+	for _, m := range b.P2P {
+		for _, side := range []string{"BUY", "SELL"} {
+			acc.AddError(b.gatherP2PSide(acc, m, side))
+		}
+	}
+}
+
+func (b *Binance) gatherP2PSide(acc telegraf.Accumulator, m *p2pMarket, side string) error {
+	body, err := json.Marshal(&p2pRequest{
+		Asset:     m.Asset,
+		Fiat:      m.Fiat,
+		TradeType: side,
+		PayTypes:  m.PaymentMethods,
+		Page:      1,
+		Rows:      m.Rows,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+	r, err := newRequest(ctx, http.MethodPost, b.p2pURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	var resp p2pResponse
+	if err := b.do(r, &resp); err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("p2p search for %s/%s failed: %s (code %s)", m.Asset, m.Fiat, resp.Message, resp.Code)
+	}
+
+	tags := map[string]string{
+		"asset": m.Asset,
+		"fiat":  m.Fiat,
+		"side":  strings.ToLower(side),
+	}
+	if len(m.PaymentMethods) > 0 {
+		tags["payment_methods"] = strings.Join(m.PaymentMethods, ",")
+	}
+
+	prices := make([]float64, 0, len(resp.Data))
+	var quantity float64
+	for _, d := range resp.Data {
+		price, err := strconv.ParseFloat(d.Adv.Price, 64)
+		if err != nil {
+			return fmt.Errorf("cannot parse p2p price %q: %w", d.Adv.Price, err)
+		}
+		prices = append(prices, price)
+		if q, err := strconv.ParseFloat(d.Adv.TradableQuantity, 64); err == nil {
+			quantity += q
+		}
+	}
+
+	fields := map[string]interface{}{"ads": len(prices)}
+	if len(prices) > 0 {
+		slices.Sort(prices)
+
+		// Buying from advertisers is best at the lowest price, selling to
+		// them at the highest one.
+		fields["best_price"] = prices[0]
+		if side == "SELL" {
+			fields["best_price"] = prices[len(prices)-1]
+		}
+		fields["median_price"] = median(prices)
+		fields["tradable_quantity"] = quantity
+	}
+	acc.AddFields("binance_p2p", fields, tags)
+	return nil
+}
+
+// median of the given sorted values
+func median(values []float64) float64 {
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
@@ -0,0 +1,103 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	apiRestrictionsEndpoint string = "/account/apiRestrictions"
+	apiRestrictionsWeight   int64  = 1
+)
+
+type apiRestrictions struct {
+	IPRestrict                     bool  `json:"ipRestrict"`
+	CreateTime                     int64 `json:"createTime"`
+	EnableReading                  bool  `json:"enableReading"`
+	EnableSpotAndMarginTrading     bool  `json:"enableSpotAndMarginTrading"`
+	EnableMargin                   bool  `json:"enableMargin"`
+	EnableFutures                  bool  `json:"enableFutures"`
+	EnableVanillaOptions           bool  `json:"enableVanillaOptions"`
+	EnablePortfolioMarginTrading   bool  `json:"enablePortfolioMarginTrading"`
+	EnableWithdrawals              bool  `json:"enableWithdrawals"`
+	EnableInternalTransfer         bool  `json:"enableInternalTransfer"`
+	PermitsUniversalTransfer       bool  `json:"permitsUniversalTransfer"`
+	EnableFixAPITrade              bool  `json:"enableFixApiTrade"`
+	EnableFixReadOnly              bool  `json:"enableFixReadOnly"`
+	TradingAuthorityExpirationTime int64 `json:"tradingAuthorityExpirationTime"`
+}
+
+// permissions returns the permissions of the API key by name
+func (r *apiRestrictions) permissions() map[string]bool {
+	return map[string]bool{
+		"reading":                  r.EnableReading,
+		"spot_and_margin_trading":  r.EnableSpotAndMarginTrading,
+		"margin":                   r.EnableMargin,
+		"futures":                  r.EnableFutures,
+		"vanilla_options":          r.EnableVanillaOptions,
+		"portfolio_margin_trading": r.EnablePortfolioMarginTrading,
+		"withdrawals":              r.EnableWithdrawals,
+		"internal_transfer":        r.EnableInternalTransfer,
+		"universal_transfer":       r.PermitsUniversalTransfer,
+		"fix_api_trade":            r.EnableFixAPITrade,
+		"fix_read_only":            r.EnableFixReadOnly,
+	}
+}
+
+// Names of the permissions of an API key
+var permissionNames = slices.Sorted(maps.Keys((&apiRestrictions{}).permissions()))
+
+// gatherKeyPermissions emits the permissions and IP restriction of the API
+// key at most once per refresh interval, warning about permissions differing
+// from the expected ones.
+func (b *Binance) gatherKeyPermissions(acc telegraf.Accumulator) error {
+	if time.Since(b.permissionsQueried) < time.Duration(b.PermissionsRefresh) {
+		return nil
+	}
+	b.permissionsQueried = time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+	var restrictions apiRestrictions
+	if err := b.querySigned(ctx, b.sapiURL+apiRestrictionsEndpoint, url.Values{}, apiRestrictionsWeight, &restrictions); err != nil {
+		return fmt.Errorf("querying API key permissions failed: %w", err)
+	}
+
+	fields := map[string]interface{}{
+		"ip_restrict": restrictions.IPRestrict,
+		"create_time": restrictions.CreateTime,
+	}
+	if restrictions.TradingAuthorityExpirationTime > 0 {
+		fields["trading_authority_expiration_time"] = restrictions.TradingAuthorityExpirationTime
+	}
+	var unexpected int
+	for name, enabled := range restrictions.permissions() {
+		fields[name] = enabled
+		if len(b.ExpectedPermissions) == 0 {
+			continue
+		}
+		expected := slices.Contains(b.ExpectedPermissions, name)
+		if enabled && !expected {
+			b.Log.Warnf("API key has the unexpected permission %q", name)
+			unexpected++
+		} else if !enabled && expected {
+			b.Log.Warnf("API key lacks the expected permission %q", name)
+			unexpected++
+		}
+	}
+	if len(b.ExpectedPermissions) > 0 {
+		fields["unexpected_permissions"] = unexpected
+	}
+	if !restrictions.IPRestrict {
+		b.Log.Debug("API key is not restricted to trusted IP addresses")
+	}
+
+	acc.AddFields("binance_api_key", fields, nil)
+	return nil
+}
@@ -0,0 +1,139 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	// Positions and the account of the USDⓈ-M futures market are only
+	// available in version 2 of the API
+	futuresV2URLString string = "https://fapi.binance.com/fapi/v2"
+
+	positionRiskEndpoint   string = "/positionRisk"
+	futuresAccountEndpoint string = "/account"
+	// Request weight of both the positions and the account
+	futuresAccountWeight int64 = 5
+)
+
+// futuresPosition is a position of the account on a futures market. The
+// COIN-M futures API reports the notional value under a different name.
+type futuresPosition struct {
+	Symbol           string `json:"symbol"`
+	PositionSide     string `json:"positionSide"`
+	MarginType       string `json:"marginType"`
+	PositionAmt      string `json:"positionAmt"`
+	EntryPrice       string `json:"entryPrice"`
+	MarkPrice        string `json:"markPrice"`
+	UnRealizedProfit string `json:"unRealizedProfit"`
+	LiquidationPrice string `json:"liquidationPrice"`
+	Leverage         string `json:"leverage"`
+	IsolatedWallet   string `json:"isolatedWallet"`
+	Notional         string `json:"notional"`
+	NotionalValue    string `json:"notionalValue"`
+}
+
+type futuresAccount struct {
+	TotalMaintMargin   string `json:"totalMaintMargin"`
+	TotalMarginBalance string `json:"totalMarginBalance"`
+	Positions          []struct {
+		Symbol       string `json:"symbol"`
+		PositionSide string `json:"positionSide"`
+		MaintMargin  string `json:"maintMargin"`
+	} `json:"positions"`
+}
+
+// gatherFuturesPositions emits the open positions of the account on the
+// futures market with their liquidation price and margin ratio.
+func (b *Binance) gatherFuturesPositions(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	base := b.futuresV2URL
+	if b.Market == marketCOINMFutures {
+		base = b.coinmURL
+	}
+	var positions []futuresPosition
+	if err := b.querySigned(ctx, base+positionRiskEndpoint, url.Values{}, futuresAccountWeight, &positions); err != nil {
+		return err
+	}
+	var account futuresAccount
+	if err := b.querySigned(ctx, base+futuresAccountEndpoint, url.Values{}, futuresAccountWeight, &account); err != nil {
+		return err
+	}
+
+	maintMargins := make(map[string]string, len(account.Positions))
+	for _, p := range account.Positions {
+		maintMargins[p.Symbol+p.PositionSide] = p.MaintMargin
+	}
+	// Cross positions share the margin of the USDⓈ-M futures account
+	var crossRatio float64
+	if b.Market == marketUSDMFutures {
+		totals := make(map[string]interface{}, 2)
+		err := parseFloatFields(totals, map[string]string{
+			"maint_margin":   account.TotalMaintMargin,
+			"margin_balance": account.TotalMarginBalance,
+		})
+		if err != nil {
+			return fmt.Errorf("parsing futures account failed: %w", err)
+		}
+		maintMargin, _ := totals["maint_margin"].(float64)
+		if balance, _ := totals["margin_balance"].(float64); balance > 0 {
+			crossRatio = maintMargin / balance
+		}
+	}
+
+	for _, p := range positions {
+		notional := p.Notional
+		if notional == "" {
+			notional = p.NotionalValue
+		}
+		values := map[string]string{
+			"position_amount":   p.PositionAmt,
+			"entry_price":       p.EntryPrice,
+			"mark_price":        p.MarkPrice,
+			"unrealized_pnl":    p.UnRealizedProfit,
+			"liquidation_price": p.LiquidationPrice,
+			"leverage":          p.Leverage,
+			"isolated_wallet":   p.IsolatedWallet,
+			"notional":          notional,
+		}
+		if maintMargin, found := maintMargins[p.Symbol+p.PositionSide]; found {
+			values["maint_margin"] = maintMargin
+		}
+		fields := make(map[string]interface{}, len(values)+1)
+		if err := parseFloatFields(fields, values); err != nil {
+			return fmt.Errorf("parsing futures position of %s failed: %w", p.Symbol, err)
+		}
+		// Binance lists a position for every symbol of the market
+		if fields["position_amount"] == 0.0 {
+			continue
+		}
+
+		marginType := strings.ToLower(p.MarginType)
+		maintMargin, found := fields["maint_margin"].(float64)
+		if marginType == "isolated" && found {
+			wallet, _ := fields["isolated_wallet"].(float64)
+			pnl, _ := fields["unrealized_pnl"].(float64)
+			if wallet+pnl > 0 {
+				fields["margin_ratio"] = maintMargin / (wallet + pnl)
+			}
+		} else if marginType == "cross" && crossRatio > 0 {
+			fields["margin_ratio"] = crossRatio
+		}
+
+		tags := map[string]string{
+			"symbol":        p.Symbol,
+			"position_side": p.PositionSide,
+			"margin_type":   marginType,
+			"market":        b.Market,
+		}
+		acc.AddFields("binance_futures_position", fields, tags)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	avgPriceEndpoint string = "/avgPrice"
+	avgPriceWeight   int64  = 2
+)
+
+type symbolFilter struct {
+	FilterType        string `json:"filterType"`
+	MinPrice          string `json:"minPrice"`
+	MaxPrice          string `json:"maxPrice"`
+	TickSize          string `json:"tickSize"`
+	MinQty            string `json:"minQty"`
+	MaxQty            string `json:"maxQty"`
+	StepSize          string `json:"stepSize"`
+	MinNotional       string `json:"minNotional"`
+	MaxNotional       string `json:"maxNotional"`
+	MultiplierUp      string `json:"multiplierUp"`
+	MultiplierDown    string `json:"multiplierDown"`
+	BidMultiplierUp   string `json:"bidMultiplierUp"`
+	BidMultiplierDown string `json:"bidMultiplierDown"`
+	AskMultiplierUp   string `json:"askMultiplierUp"`
+	AskMultiplierDown string `json:"askMultiplierDown"`
+}
+
+type avgPrice struct {
+	Mins  int    `json:"mins"`
+	Price string `json:"price"`
+}
+
+// priceBands are the multipliers of the average price limiting the price of
+// orders on either side
+type priceBands struct {
+	bidUp, bidDown float64
+	askUp, askDown float64
+}
+
+// percentPriceBands returns the price bands enforced by the PERCENT_PRICE_BY_SIDE
+// or the PERCENT_PRICE filter of the symbol or nil if the symbol has neither.
+func percentPriceBands(info symbolInfo) (*priceBands, error) {
+	for _, f := range info.Filters {
+		var values []string
+		switch f.FilterType {
+		case "PERCENT_PRICE_BY_SIDE":
+			values = []string{f.BidMultiplierUp, f.BidMultiplierDown, f.AskMultiplierUp, f.AskMultiplierDown}
+		case "PERCENT_PRICE":
+			values = []string{f.MultiplierUp, f.MultiplierDown, f.MultiplierUp, f.MultiplierDown}
+		default:
+			continue
+		}
+
+		multipliers := make([]float64, 0, len(values))
+		for _, v := range values {
+			m, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse multiplier %q of %s filter of %s: %w", v, f.FilterType, info.Symbol, err)
+			}
+			multipliers = append(multipliers, m)
+		}
+		return &priceBands{
+			bidUp:   multipliers[0],
+			bidDown: multipliers[1],
+			askUp:   multipliers[2],
+			askDown: multipliers[3],
+		}, nil
+	}
+	return nil, nil
+}
+
+// averagePrice queries the average price of the pair and the number of
+// minutes averaged over
+func (b *Binance) averagePrice(ctx context.Context, p *pair) (float64, int, error) {
+	var avg avgPrice
+	if err := b.query(ctx, avgPriceEndpoint, p.query(), avgPriceWeight, &avg); err != nil {
+		return 0, 0, err
+	}
+	average, err := strconv.ParseFloat(avg.Price, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("cannot parse average price %q of %s: %w", avg.Price, p.symbol, err)
+	}
+	return average, avg.Mins, nil
+}
+
+// addPriceBands emits the bands around the average price enforced by the
+// exchange and the distance of the given price to them.
+func addPriceBands(acc telegraf.Accumulator, p *pair, price, average float64, mins int) {
+	sides := []struct {
+		name     string
+		up, down float64
+	}{{"bid", p.bands.bidUp, p.bands.bidDown}, {"ask", p.bands.askUp, p.bands.askDown}}
+	for _, side := range sides {
+		lower, upper := average*side.down, average*side.up
+		fields := map[string]interface{}{
+			"avg_price":              average,
+			"avg_price_mins":         mins,
+			"lower":                  lower,
+			"upper":                  upper,
+			"lower_distance_percent": (price - lower) / price * 100,
+			"upper_distance_percent": (upper - price) / price * 100,
+		}
+		acc.AddFields("binance_price_band", fields, p.tagsWith("side", side.name))
+	}
+}
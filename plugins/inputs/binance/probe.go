@@ -0,0 +1,76 @@
+package binance
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	pingEndpoint string = "/ping"
+	pingWeight   int64  = 1
+)
+
+// Regional hosts of the Binance spot API
+var probeHosts = []string{
+	"api.binance.com",
+	"api-gcp.binance.com",
+	"api1.binance.com",
+	"api2.binance.com",
+	"api3.binance.com",
+	"api4.binance.com",
+}
+
+// probeResult is the round-trip time of a ping to a host
+type probeResult struct {
+	host    string
+	latency time.Duration
+	err     error
+}
+
+// gatherProbes measures the round-trip latency to the regional API hosts and
+// ranks the reachable ones by latency.
+func (b *Binance) gatherProbes(acc telegraf.Accumulator) {
+	if time.Since(b.probed) < time.Duration(b.ProbeInterval) {
+		return
+	}
+	b.probed = time.Now()
+
+	results := make([]probeResult, 0, len(b.probeURLs))
+	for _, host := range probeHosts {
+		address, found := b.probeURLs[host]
+		if !found {
+			continue
+		}
+		results = append(results, b.probe(host, address))
+	}
+
+	// Rank the reachable hosts from the lowest latency on
+	sort.SliceStable(results, func(i, j int) bool {
+		if (results[i].err == nil) != (results[j].err == nil) {
+			return results[i].err == nil
+		}
+		return results[i].latency < results[j].latency
+	})
+	for i, r := range results {
+		fields := map[string]interface{}{"reachable": r.err == nil}
+		if r.err == nil {
+			fields["latency"] = r.latency.Seconds()
+			fields["rank"] = i + 1
+		} else {
+			b.Log.Debugf("Probing %s failed: %v", r.host, r.err)
+		}
+		acc.AddFields("binance_endpoint_probe", fields, map[string]string{"host": r.host}, b.probed)
+	}
+}
+
+func (b *Binance) probe(host, address string) probeResult {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	start := time.Now()
+	err := b.queryURL(ctx, address+pingEndpoint, nil, pingWeight, nil)
+	return probeResult{host: host, latency: time.Since(start), err: err}
+}
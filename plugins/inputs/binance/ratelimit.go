@@ -0,0 +1,125 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/common/ratelimiter"
+)
+
+// Binance accounts request weight per IP in windows of one minute
+const rateLimitPeriod = time.Minute
+
+var (
+	errBudgetExhausted = errors.New("request-weight budget exhausted")
+	errBanned          = errors.New("requests blocked by Binance")
+)
+
+// weightBudget is a request-weight budget which might be shared by
+// multiple plugin instances.
+type weightBudget struct {
+	limit   int64
+	limiter *ratelimiter.RateLimiter
+	// End of the back-off requested by Binance via a 429 or 418 response
+	// and the status code of that response
+	blockedUntil  time.Time
+	blockedStatus int
+	sync.Mutex
+}
+
+// Budgets shared across plugin instances in the same agent, keyed by the
+// configured rate-limit group.
+var (
+	sharedBudgets   = make(map[string]*weightBudget)
+	sharedBudgetsMu sync.Mutex
+)
+
+func newWeightBudget(limit int64) (*weightBudget, error) {
+	cfg := &ratelimiter.RateLimitConfig{
+		Limit:  config.Size(limit),
+		Period: config.Duration(rateLimitPeriod),
+	}
+	limiter, err := cfg.CreateRateLimiter()
+	if err != nil {
+		return nil, err
+	}
+	return &weightBudget{limit: limit, limiter: limiter}, nil
+}
+
+// sharedBudget returns the budget registered for the given group, creating
+// it with the given limit if it does not exist yet.
+func sharedBudget(group string, limit int64) (*weightBudget, error) {
+	sharedBudgetsMu.Lock()
+	defer sharedBudgetsMu.Unlock()
+
+	if b, found := sharedBudgets[group]; found {
+		return b, nil
+	}
+
+	b, err := newWeightBudget(limit)
+	if err != nil {
+		return nil, err
+	}
+	sharedBudgets[group] = b
+	return b, nil
+}
+
+// reserve takes the given weight from the budget or returns an error if
+// not enough weight is left in the current period.
+func (b *weightBudget) reserve(t time.Time, weight int64) error {
+	b.Lock()
+	defer b.Unlock()
+
+	if t.Before(b.blockedUntil) {
+		return fmt.Errorf("%w until %s", errBanned, b.blockedUntil.Format(time.RFC3339))
+	}
+	if b.limiter.Remaining(t) < weight {
+		return errBudgetExhausted
+	}
+	b.limiter.Accept(t, weight)
+	return nil
+}
+
+// wait blocks until the given weight could be taken from the budget or the
+// context is done.
+func (b *weightBudget) wait(ctx context.Context, weight int64) error {
+	for {
+		if err := b.reserve(time.Now(), weight); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// block rejects all requests until the given time due to a response with
+// the given status code
+func (b *weightBudget) block(until time.Time, statusCode int) {
+	b.Lock()
+	defer b.Unlock()
+
+	if until.After(b.blockedUntil) {
+		b.blockedUntil = until
+		b.blockedStatus = statusCode
+	}
+}
+
+// blocked returns the end of the current back-off and the status code of the
+// response causing it or the zero time if requests are not blocked at the
+// given time
+func (b *weightBudget) blocked(t time.Time) (time.Time, int) {
+	b.Lock()
+	defer b.Unlock()
+
+	if t.Before(b.blockedUntil) {
+		return b.blockedUntil, b.blockedStatus
+	}
+	return time.Time{}, 0
+}
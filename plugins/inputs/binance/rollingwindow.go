@@ -0,0 +1,81 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	rollingWindowEndpoint string = "/ticker"
+	// Request weight per symbol, the maximum number of symbols per request
+	// and the maximum weight of a single request
+	rollingWindowWeight     int64 = 4
+	rollingWindowMaxSymbols int   = 100
+	rollingWindowMaxWeight  int64 = 200
+)
+
+// Window sizes accepted by Binance, i.e. 1m to 59m, 1h to 23h or 1d to 7d
+var rollingWindowSize = regexp.MustCompile(`^(([1-9]|[1-5]\d)m|([1-9]|1\d|2[0-3])h|[1-7]d)$`)
+
+// gatherRollingWindows emits the price-change statistics of the configured
+// rolling windows for all pairs.
+func (b *Binance) gatherRollingWindows(acc telegraf.Accumulator) {
+	for _, window := range b.RollingWindows {
+		for start := 0; start < len(b.pairs); start += rollingWindowMaxSymbols {
+			end := min(start+rollingWindowMaxSymbols, len(b.pairs))
+			acc.AddError(b.gatherRollingWindowBatch(acc, window, b.pairs[start:end]))
+		}
+	}
+}
+
+func (b *Binance) gatherRollingWindowBatch(acc telegraf.Accumulator, window string, pairs []*pair) error {
+	symbols := make([]string, 0, len(pairs))
+	for _, p := range pairs {
+		symbols = append(symbols, p.symbol)
+	}
+	buf, err := json.Marshal(symbols)
+	if err != nil {
+		return err
+	}
+	params := url.Values{
+		"symbols":    {string(buf)},
+		"windowSize": {window},
+		"type":       {"FULL"},
+	}
+	weight := min(rollingWindowWeight*int64(len(symbols)), rollingWindowMaxWeight)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var tickers []tickerStatistics
+	if err := b.query(ctx, rollingWindowEndpoint, params, weight, &tickers); err != nil {
+		return err
+	}
+
+	bySymbol := make(map[string]tickerStatistics, len(tickers))
+	for _, t := range tickers {
+		bySymbol[t.Symbol] = t
+	}
+	for _, p := range pairs {
+		t, found := bySymbol[p.symbol]
+		if !found {
+			acc.AddError(fmt.Errorf("no %s rolling-window statistics received for symbol %s", window, p.symbol))
+			continue
+		}
+
+		fields, err := t.fields()
+		if err != nil {
+			acc.AddError(fmt.Errorf("parsing %s rolling-window statistics of %s failed: %w", window, p.symbol, err))
+			continue
+		}
+
+		acc.AddFields("binance_rolling_window", fields, p.tagsWith("window", window))
+	}
+	return nil
+}
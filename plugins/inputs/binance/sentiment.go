@@ -0,0 +1,105 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	takerVolumeEndpoint string = "/takerlongshortRatio"
+	// Request weight of each of the long/short ratio and taker volume statistics
+	sentimentWeight int64 = 1
+)
+
+// Endpoints of the long/short ratios by the type reported
+var longShortRatioEndpoints = map[string]string{
+	"global_accounts": "/globalLongShortAccountRatio",
+	"top_accounts":    "/topLongShortAccountRatio",
+	"top_positions":   "/topLongShortPositionRatio",
+}
+
+// longShortRatio is the ratio of long to short accounts respectively of
+// positions of the top traders, with the shares of long and short ones.
+type longShortRatio struct {
+	LongShortRatio string `json:"longShortRatio"`
+	LongAccount    string `json:"longAccount"`
+	ShortAccount   string `json:"shortAccount"`
+	Timestamp      int64  `json:"timestamp"`
+}
+
+type takerVolume struct {
+	BuySellRatio string `json:"buySellRatio"`
+	BuyVol       string `json:"buyVol"`
+	SellVol      string `json:"sellVol"`
+	Timestamp    int64  `json:"timestamp"`
+}
+
+// gatherSentiment emits the long/short ratios and the taker buy and sell
+// volume of the contracts for the periods completed since the last reported
+// one.
+func (b *Binance) gatherSentiment(acc telegraf.Accumulator) {
+	for _, p := range b.pairs {
+		for kind, endpoint := range longShortRatioEndpoints {
+			acc.AddError(b.gatherLongShortRatio(acc, p, kind, endpoint))
+		}
+		acc.AddError(b.gatherTakerVolume(acc, p))
+	}
+}
+
+func (b *Binance) gatherLongShortRatio(acc telegraf.Accumulator, p *pair, kind, endpoint string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	params := b.futuresDataQuery(endpoint, p, b.SentimentPeriod)
+	var ratios []longShortRatio
+	if err := b.queryURL(ctx, b.futuresDataURL+endpoint, params, sentimentWeight, &ratios); err != nil {
+		return err
+	}
+
+	tags := p.tagsWith("period", b.SentimentPeriod)
+	tags["type"] = kind
+	for _, r := range ratios {
+		fields := make(map[string]interface{}, 3)
+		err := parseFloatFields(fields, map[string]string{
+			"long_short_ratio": r.LongShortRatio,
+			"long_share":       r.LongAccount,
+			"short_share":      r.ShortAccount,
+		})
+		if err != nil {
+			return fmt.Errorf("parsing %s long/short ratio of %s failed: %w", kind, p.symbol, err)
+		}
+		acc.AddFields("binance_long_short_ratio", fields, tags, time.UnixMilli(r.Timestamp))
+		b.futuresDataSeen[endpoint+p.symbol] = r.Timestamp
+	}
+	return nil
+}
+
+func (b *Binance) gatherTakerVolume(acc telegraf.Accumulator, p *pair) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	params := b.futuresDataQuery(takerVolumeEndpoint, p, b.SentimentPeriod)
+	var volumes []takerVolume
+	if err := b.queryURL(ctx, b.futuresDataURL+takerVolumeEndpoint, params, sentimentWeight, &volumes); err != nil {
+		return err
+	}
+
+	tags := p.tagsWith("period", b.SentimentPeriod)
+	for _, v := range volumes {
+		fields := make(map[string]interface{}, 3)
+		err := parseFloatFields(fields, map[string]string{
+			"buy_sell_ratio": v.BuySellRatio,
+			"buy_volume":     v.BuyVol,
+			"sell_volume":    v.SellVol,
+		})
+		if err != nil {
+			return fmt.Errorf("parsing taker volume of %s failed: %w", p.symbol, err)
+		}
+		acc.AddFields("binance_taker_volume", fields, tags, time.UnixMilli(v.Timestamp))
+		b.futuresDataSeen[takerVolumeEndpoint+p.symbol] = v.Timestamp
+	}
+	return nil
+}
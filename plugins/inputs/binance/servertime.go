@@ -0,0 +1,41 @@
+package binance
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	serverTimeEndpoint string = "/time"
+	serverTimeWeight   int64  = 1
+	// Binance error code for timestamps outside of the receive window
+	codeInvalidTimestamp = -1021
+)
+
+type serverTime struct {
+	ServerTime int64 `json:"serverTime"`
+}
+
+// syncServerTime measures the offset of the local clock to the server time
+// once per server_time_refresh interval. Signed requests are timestamped with
+// the corrected time, as Binance rejects requests with timestamps outside the
+// receive window.
+func (b *Binance) syncServerTime(ctx context.Context) error {
+	if time.Since(b.serverTimeSynced) < time.Duration(b.ServerTimeRefresh) {
+		return nil
+	}
+
+	start := time.Now()
+	var st serverTime
+	if err := b.query(ctx, serverTimeEndpoint, nil, serverTimeWeight, &st); err != nil {
+		return err
+	}
+	end := time.Now()
+
+	// Assume the server took the time halfway through the round trip
+	local := start.Add(end.Sub(start) / 2)
+	b.serverTimeOffset = time.UnixMilli(st.ServerTime).Sub(local)
+	b.serverTimeSynced = end
+	b.Log.Debugf("Offset of the local clock to the server time is %s", -b.serverTimeOffset)
+	return nil
+}
@@ -0,0 +1,62 @@
+package binance
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// addSlippage emits the estimated execution of market orders of the
+// configured sizes by filling them with the order-book levels from the best
+// price on.
+func (b *Binance) addSlippage(acc telegraf.Accumulator, p *pair, bids, asks []bookLevel, ts time.Time) {
+	// Empty sides are already reported by the book statistics
+	if len(bids) == 0 || len(asks) == 0 {
+		return
+	}
+	mid := (bids[0].price + asks[0].price) / 2
+
+	// Buy orders are filled by the asks, sell orders by the bids
+	sides := []struct {
+		name   string
+		levels []bookLevel
+		sign   float64
+	}{{"buy", asks, 1}, {"sell", bids, -1}}
+	for _, size := range b.SlippageOrderSizes {
+		for _, side := range sides {
+			filled, cost, worst, complete := fillOrder(side.levels, size)
+
+			tags := p.tagsWith("side", side.name)
+			tags["order_size"] = strconv.FormatFloat(size, 'f', -1, 64)
+			fields := map[string]interface{}{
+				"filled_quantity": filled,
+				"complete":        complete,
+			}
+			if filled > 0 {
+				price := cost / filled
+				fields["avg_price"] = price
+				fields["worst_price"] = worst
+				fields["slippage_percent"] = side.sign * (price - mid) / mid * 100
+			}
+			acc.AddFields("binance_slippage", fields, tags, ts)
+		}
+	}
+}
+
+// fillOrder fills an order of the given size with the levels from the best
+// price on and returns the filled quantity, its cost, the price of the last
+// level used and whether the levels sufficed to fill the order completely.
+func fillOrder(levels []bookLevel, size float64) (filled, cost, worst float64, complete bool) {
+	remaining := size
+	for _, l := range levels {
+		take := min(remaining, l.quantity)
+		filled += take
+		cost += take * l.price
+		worst = l.price
+		if remaining -= take; remaining <= 0 {
+			return filled, cost, worst, true
+		}
+	}
+	return filled, cost, worst, false
+}
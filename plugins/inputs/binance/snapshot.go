@@ -0,0 +1,187 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	accountSnapshotEndpoint string = "/accountSnapshot"
+	accountSnapshotWeight   int64  = 2400
+	// Maximum number of daily snapshots returned per request
+	accountSnapshotLimit int = 30
+	// Snapshots are taken daily, so there is no need to query them often
+	accountSnapshotRefresh = time.Hour
+)
+
+// Wallet types of the daily account snapshots
+var snapshotTypes = []string{"spot", "margin", "futures"}
+
+type accountSnapshots struct {
+	Code        int    `json:"code"`
+	Msg         string `json:"msg"`
+	SnapshotVos []struct {
+		Type       string          `json:"type"`
+		UpdateTime int64           `json:"updateTime"`
+		Data       json.RawMessage `json:"data"`
+	} `json:"snapshotVos"`
+}
+
+type spotSnapshot struct {
+	TotalAssetOfBtc string `json:"totalAssetOfBtc"`
+	Balances        []struct {
+		Asset  string `json:"asset"`
+		Free   string `json:"free"`
+		Locked string `json:"locked"`
+	} `json:"balances"`
+}
+
+type marginSnapshot struct {
+	MarginLevel         string        `json:"marginLevel"`
+	TotalAssetOfBtc     string        `json:"totalAssetOfBtc"`
+	TotalLiabilityOfBtc string        `json:"totalLiabilityOfBtc"`
+	TotalNetAssetOfBtc  string        `json:"totalNetAssetOfBtc"`
+	UserAssets          []marginAsset `json:"userAssets"`
+}
+
+type futuresSnapshot struct {
+	Assets []struct {
+		Asset         string `json:"asset"`
+		MarginBalance string `json:"marginBalance"`
+		WalletBalance string `json:"walletBalance"`
+	} `json:"assets"`
+}
+
+// gatherAccountSnapshots emits the daily snapshots of the configured wallets
+// taken since the last reported one, querying them at most once per hour
+func (b *Binance) gatherAccountSnapshots(acc telegraf.Accumulator) {
+	if time.Since(b.snapshotsQueried) < accountSnapshotRefresh {
+		return
+	}
+	b.snapshotsQueried = time.Now()
+
+	for _, kind := range b.AccountSnapshots {
+		acc.AddError(b.gatherAccountSnapshot(acc, kind))
+	}
+}
+
+func (b *Binance) gatherAccountSnapshot(acc telegraf.Accumulator, kind string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	// Without a known last snapshot, Binance returns the snapshots of the last
+	// week
+	params := url.Values{
+		"type":  {strings.ToUpper(kind)},
+		"limit": {strconv.Itoa(accountSnapshotLimit)},
+	}
+	last, found := b.state.LastSnapshot[kind]
+	if found {
+		params.Set("startTime", strconv.FormatInt(last+1, 10))
+	}
+	var snapshots accountSnapshots
+	if err := b.querySigned(ctx, b.sapiURL+accountSnapshotEndpoint, params, accountSnapshotWeight, &snapshots); err != nil {
+		return err
+	}
+	if snapshots.Code != 200 {
+		return fmt.Errorf("querying %s account snapshots failed: %s (code %d)", kind, snapshots.Msg, snapshots.Code)
+	}
+
+	for _, s := range snapshots.SnapshotVos {
+		if s.UpdateTime <= last {
+			continue
+		}
+		var err error
+		ts := time.UnixMilli(s.UpdateTime)
+		switch kind {
+		case "spot":
+			err = addSpotSnapshot(acc, s.Data, ts)
+		case "margin":
+			err = addMarginSnapshot(acc, s.Data, ts)
+		case "futures":
+			err = addFuturesSnapshot(acc, s.Data, ts)
+		}
+		if err != nil {
+			return fmt.Errorf("parsing %s account snapshot of %s failed: %w", kind, ts.Format(time.DateOnly), err)
+		}
+		b.state.LastSnapshot[kind] = max(b.state.LastSnapshot[kind], s.UpdateTime)
+	}
+	return nil
+}
+
+func addSpotSnapshot(acc telegraf.Accumulator, data json.RawMessage, ts time.Time) error {
+	var snapshot spotSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+
+	fields := make(map[string]interface{}, 1)
+	if err := parseFloatFields(fields, map[string]string{"total_asset_btc": snapshot.TotalAssetOfBtc}); err != nil {
+		return err
+	}
+	acc.AddFields("binance_account_snapshot", fields, map[string]string{"type": "spot"}, ts)
+
+	for _, bal := range snapshot.Balances {
+		fields := make(map[string]interface{}, 2)
+		if err := parseFloatFields(fields, map[string]string{"free": bal.Free, "locked": bal.Locked}); err != nil {
+			return err
+		}
+		acc.AddFields("binance_snapshot_balance", fields, map[string]string{"type": "spot", "asset": bal.Asset}, ts)
+	}
+	return nil
+}
+
+func addMarginSnapshot(acc telegraf.Accumulator, data json.RawMessage, ts time.Time) error {
+	var snapshot marginSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+
+	fields := make(map[string]interface{}, 4)
+	err := parseFloatFields(fields, map[string]string{
+		"margin_level":        snapshot.MarginLevel,
+		"total_asset_btc":     snapshot.TotalAssetOfBtc,
+		"total_liability_btc": snapshot.TotalLiabilityOfBtc,
+		"total_net_asset_btc": snapshot.TotalNetAssetOfBtc,
+	})
+	if err != nil {
+		return err
+	}
+	acc.AddFields("binance_account_snapshot", fields, map[string]string{"type": "margin"}, ts)
+
+	for _, a := range snapshot.UserAssets {
+		fields, err := a.fields()
+		if err != nil {
+			return err
+		}
+		acc.AddFields("binance_snapshot_balance", fields, map[string]string{"type": "margin", "asset": a.Asset}, ts)
+	}
+	return nil
+}
+
+func addFuturesSnapshot(acc telegraf.Accumulator, data json.RawMessage, ts time.Time) error {
+	var snapshot futuresSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+
+	for _, a := range snapshot.Assets {
+		fields := make(map[string]interface{}, 2)
+		err := parseFloatFields(fields, map[string]string{
+			"margin_balance": a.MarginBalance,
+			"wallet_balance": a.WalletBalance,
+		})
+		if err != nil {
+			return err
+		}
+		acc.AddFields("binance_snapshot_balance", fields, map[string]string{"type": "futures", "asset": a.Asset}, ts)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	ethStakingAccountEndpoint string = "/eth-staking/account"
+	ethStakingRateEndpoint    string = "/eth-staking/eth/history/rateHistory"
+	solStakingAccountEndpoint string = "/sol-staking/account"
+	solStakingRateEndpoint    string = "/sol-staking/sol/history/rateHistory"
+	stakingWeight             int64  = 150
+)
+
+// Assets supported by the staking collection
+var stakingAssets = []string{"ETH", "SOL"}
+
+type ethStakingAccount struct {
+	HoldingInETH          string `json:"holdingInETH"`
+	ThirtyDaysProfitInETH string `json:"thirtyDaysProfitInETH"`
+	Holdings              struct {
+		WBETHAmount string `json:"wbethAmount"`
+	} `json:"holdings"`
+}
+
+type solStakingAccount struct {
+	BNSOLAmount           string `json:"bnsolAmount"`
+	HoldingInSOL          string `json:"holdingInSOL"`
+	ThirtyDaysProfitInSOL string `json:"thirtyDaysProfitInSOL"`
+}
+
+type stakingRateHistory struct {
+	Rows []struct {
+		AnnualPercentageRate string `json:"annualPercentageRate"`
+		ExchangeRate         string `json:"exchangeRate"`
+	} `json:"rows"`
+}
+
+// gatherStaking emits the staked amount, the profit of the last 30 days and
+// the current exchange rate and annual rate of the liquid-staking token of
+// the configured assets
+func (b *Binance) gatherStaking(acc telegraf.Accumulator) {
+	for _, asset := range b.Staking {
+		acc.AddError(b.gatherStakingAsset(acc, asset))
+	}
+}
+
+func (b *Binance) gatherStakingAsset(acc telegraf.Accumulator, asset string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	fields := make(map[string]interface{}, 5)
+	var rateAddress string
+	var token string
+	switch asset {
+	case "ETH":
+		var account ethStakingAccount
+		if err := b.querySigned(ctx, b.sapiV2URL+ethStakingAccountEndpoint, url.Values{}, stakingWeight, &account); err != nil {
+			return err
+		}
+		err := parseFloatFields(fields, map[string]string{
+			"holding":            account.HoldingInETH,
+			"token_amount":       account.Holdings.WBETHAmount,
+			"thirty_days_profit": account.ThirtyDaysProfitInETH,
+		})
+		if err != nil {
+			return fmt.Errorf("parsing ETH staking account failed: %w", err)
+		}
+		rateAddress, token = b.sapiURL+ethStakingRateEndpoint, "WBETH"
+	case "SOL":
+		var account solStakingAccount
+		if err := b.querySigned(ctx, b.sapiURL+solStakingAccountEndpoint, url.Values{}, stakingWeight, &account); err != nil {
+			return err
+		}
+		err := parseFloatFields(fields, map[string]string{
+			"holding":            account.HoldingInSOL,
+			"token_amount":       account.BNSOLAmount,
+			"thirty_days_profit": account.ThirtyDaysProfitInSOL,
+		})
+		if err != nil {
+			return fmt.Errorf("parsing SOL staking account failed: %w", err)
+		}
+		rateAddress, token = b.sapiURL+solStakingRateEndpoint, "BNSOL"
+	}
+
+	// The history starts with the most recent rate
+	var history stakingRateHistory
+	if err := b.querySigned(ctx, rateAddress, url.Values{"size": {"1"}}, stakingWeight, &history); err != nil {
+		return err
+	}
+	if len(history.Rows) > 0 {
+		latest := history.Rows[0]
+		err := parseFloatFields(fields, map[string]string{
+			"exchange_rate": latest.ExchangeRate,
+			"apr":           latest.AnnualPercentageRate,
+		})
+		if err != nil {
+			return fmt.Errorf("parsing %s staking rate failed: %w", asset, err)
+		}
+	}
+
+	acc.AddFields("binance_staking", fields, map[string]string{"asset": asset, "token": token})
+	return nil
+}
@@ -0,0 +1,73 @@
+package binance
+
+import (
+	"fmt"
+	"time"
+)
+
+// state of the plugin persisted across Telegraf runs
+type state struct {
+	// Time of the last emitted price per symbol
+	LastPrice map[string]time.Time `json:"last_price,omitempty"`
+	// Release time of the newest announcement per category in milliseconds
+	LastAnnouncement map[string]int64 `json:"last_announcement,omitempty"`
+	// Identifier of the last reported historical trade per symbol
+	LastTradeID map[string]int64 `json:"last_trade_id,omitempty"`
+	// Identifier of the last reported aggregate trade per symbol
+	LastAggTradeID map[string]int64 `json:"last_agg_trade_id,omitempty"`
+	// Identifier of the last reported fill of the account per symbol
+	LastMyTradeID map[string]int64 `json:"last_my_trade_id,omitempty"`
+	// Time of the last reported deposit and withdrawal in milliseconds
+	LastTransfer map[string]int64 `json:"last_transfer,omitempty"`
+	// Deposits and withdrawals not yet in a final status, see transferKey
+	PendingTransfer map[string]pendingTransfer `json:"pending_transfer,omitempty"`
+	// Time of the last reported daily account snapshot per wallet type
+	LastSnapshot map[string]int64 `json:"last_snapshot,omitempty"`
+	// Time of the last reported settled funding rate per symbol
+	LastFunding map[string]int64 `json:"last_funding,omitempty"`
+	// Close time of the last reported kline per symbol and interval, see
+	// klineKey
+	LastKline map[string]time.Time `json:"last_kline,omitempty"`
+}
+
+func (b *Binance) GetState() interface{} {
+	return b.state
+}
+
+func (b *Binance) SetState(s interface{}) error {
+	restored, ok := s.(state)
+	if !ok {
+		return fmt.Errorf("state has wrong type %T", s)
+	}
+	for symbol, t := range restored.LastPrice {
+		b.state.LastPrice[symbol] = t
+	}
+	for category, t := range restored.LastAnnouncement {
+		b.state.LastAnnouncement[category] = t
+	}
+	for symbol, id := range restored.LastTradeID {
+		b.state.LastTradeID[symbol] = id
+	}
+	for symbol, id := range restored.LastAggTradeID {
+		b.state.LastAggTradeID[symbol] = id
+	}
+	for symbol, id := range restored.LastMyTradeID {
+		b.state.LastMyTradeID[symbol] = id
+	}
+	for kind, t := range restored.LastTransfer {
+		b.state.LastTransfer[kind] = t
+	}
+	for key, pending := range restored.PendingTransfer {
+		b.state.PendingTransfer[key] = pending
+	}
+	for kind, t := range restored.LastSnapshot {
+		b.state.LastSnapshot[kind] = t
+	}
+	for symbol, t := range restored.LastFunding {
+		b.state.LastFunding[symbol] = t
+	}
+	for key, t := range restored.LastKline {
+		b.state.LastKline[key] = t
+	}
+	return nil
+}
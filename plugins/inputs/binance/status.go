@@ -0,0 +1,39 @@
+package binance
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	systemStatusEndpoint string = "/system/status"
+	systemStatusWeight   int64  = 1
+)
+
+type systemStatus struct {
+	// Zero for normal operation, one for system maintenance
+	Status int    `json:"status"`
+	Msg    string `json:"msg"`
+}
+
+// gatherSystemStatus emits whether the exchange is under maintenance to tell
+// outages of Binance from failures of the collection.
+func (b *Binance) gatherSystemStatus(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var status systemStatus
+	if err := b.queryURL(ctx, b.sapiURL+systemStatusEndpoint, nil, systemStatusWeight, &status); err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{
+		"status":      status.Status,
+		"message":     status.Msg,
+		"maintenance": status.Status != 0,
+	}
+	acc.AddFields("binance_status", fields, nil)
+	return nil
+}
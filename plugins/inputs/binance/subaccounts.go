@@ -0,0 +1,111 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	sapiV4URLString          string = "https://api.binance.com/sapi/v4"
+	subAccountListEndpoint   string = "/sub-account/list"
+	subAccountAssetsEndpoint string = "/sub-account/assets"
+	subAccountListWeight     int64  = 1
+	subAccountAssetsWeight   int64  = 60
+	// Maximum number of sub-accounts returned per request
+	subAccountListLimit int = 200
+)
+
+type subAccountList struct {
+	SubAccounts []struct {
+		Email    string `json:"email"`
+		IsFreeze bool   `json:"isFreeze"`
+	} `json:"subAccounts"`
+}
+
+type subAccountAssets struct {
+	// Depending on the API version the amounts are numbers or strings
+	Balances []struct {
+		Asset  string      `json:"asset"`
+		Free   json.Number `json:"free"`
+		Locked json.Number `json:"locked"`
+	} `json:"balances"`
+}
+
+// gatherSubAccounts emits the balances of all sub-accounts of the master
+// account tagged with the email address of the sub-account
+func (b *Binance) gatherSubAccounts(acc telegraf.Accumulator) error {
+	emails, err := b.subAccounts()
+	if err != nil {
+		return err
+	}
+	for _, email := range emails {
+		acc.AddError(b.gatherSubAccountBalances(acc, email))
+	}
+	return nil
+}
+
+// subAccounts returns the email addresses of all sub-accounts
+func (b *Binance) subAccounts() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var emails []string
+	for page := 1; ; page++ {
+		params := url.Values{
+			"page":  {strconv.Itoa(page)},
+			"limit": {strconv.Itoa(subAccountListLimit)},
+		}
+		var list subAccountList
+		if err := b.querySigned(ctx, b.sapiURL+subAccountListEndpoint, params, subAccountListWeight, &list); err != nil {
+			return nil, fmt.Errorf("listing sub-accounts failed: %w", err)
+		}
+		for _, s := range list.SubAccounts {
+			emails = append(emails, s.Email)
+		}
+		if len(list.SubAccounts) < subAccountListLimit {
+			return emails, nil
+		}
+	}
+}
+
+func (b *Binance) gatherSubAccountBalances(acc telegraf.Accumulator, email string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var assets subAccountAssets
+	params := url.Values{"email": {email}}
+	if err := b.querySigned(ctx, b.sapiV4URL+subAccountAssetsEndpoint, params, subAccountAssetsWeight, &assets); err != nil {
+		return fmt.Errorf("querying assets of sub-account %s failed: %w", email, err)
+	}
+
+	for _, bal := range assets.Balances {
+		free, err := bal.Free.Float64()
+		if err != nil {
+			return fmt.Errorf("cannot parse free balance %q of %s in sub-account %s: %w", bal.Free, bal.Asset, email, err)
+		}
+		locked, err := bal.Locked.Float64()
+		if err != nil {
+			return fmt.Errorf("cannot parse locked balance %q of %s in sub-account %s: %w", bal.Locked, bal.Asset, email, err)
+		}
+		if free == 0 && locked == 0 {
+			continue
+		}
+		fields := map[string]interface{}{
+			"free":   free,
+			"locked": locked,
+			"total":  free + locked,
+		}
+		tags := map[string]string{
+			"asset":             bal.Asset,
+			"sub_account_email": email,
+		}
+		acc.AddFields("binance_balance", fields, tags)
+	}
+	return nil
+}
@@ -0,0 +1,342 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"time"
+)
+
+// Binance error code for requests containing an unknown symbol
+const codeInvalidSymbol = -1121
+
+type exchangeInfo struct {
+	Symbols []symbolInfo `json:"symbols"`
+}
+
+type symbolInfo struct {
+	Symbol       string         `json:"symbol"`
+	Status       string         `json:"status"`
+	BaseAsset    string         `json:"baseAsset"`
+	QuoteAsset   string         `json:"quoteAsset"`
+	ContractType string         `json:"contractType"`
+	Filters      []symbolFilter `json:"filters"`
+}
+
+// UnmarshalJSON takes the status of COIN-M futures symbols from their
+// contractStatus field
+func (s *symbolInfo) UnmarshalJSON(data []byte) error {
+	type plain symbolInfo
+	var v struct {
+		plain
+		ContractStatus string `json:"contractStatus"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = symbolInfo(v.plain)
+	if s.Status == "" {
+		s.Status = v.ContractStatus
+	}
+	return nil
+}
+
+// pair is a symbol traded on the exchange
+type pair struct {
+	symbol string
+	status string
+	tags   map[string]string
+	bands  *priceBands
+	// The price of an inverted pair is reported as the inverse of the price
+	// of the symbol with the price tags swapped accordingly.
+	inverted  bool
+	priceTags map[string]string
+}
+
+func newPair(info symbolInfo) *pair {
+	tags := map[string]string{
+		"base":  info.BaseAsset,
+		"quote": info.QuoteAsset,
+	}
+	return &pair{
+		symbol:    info.Symbol,
+		status:    info.Status,
+		tags:      tags,
+		priceTags: tags,
+	}
+}
+
+// invert reports the price of the pair as the inverse of the symbol's price
+func (p *pair) invert() {
+	p.inverted = true
+	p.priceTags = map[string]string{
+		"base":     p.tags["quote"],
+		"quote":    p.tags["base"],
+		"inverted": "true",
+	}
+}
+
+// price returns the price to report for the given price of the symbol
+func (p *pair) price(price float64) float64 {
+	if p.inverted && price != 0 {
+		return 1 / price
+	}
+	return price
+}
+
+func (p *pair) query() url.Values {
+	return url.Values{"symbol": {p.symbol}}
+}
+
+// tagsWith returns a copy of the pair's tags with the given tag added
+func (p *pair) tagsWith(key, value string) map[string]string {
+	tags := make(map[string]string, len(p.tags)+1)
+	for k, v := range p.tags {
+		tags[k] = v
+	}
+	tags[key] = value
+	return tags
+}
+
+// candidates returns the symbols to check against the exchange
+func (b *Binance) candidates() []string {
+	symbols := make([]string, 0, len(b.Symbols)+len(b.QuoteAssets)+2)
+	if b.Symbol != "" {
+		symbols = append(symbols, b.Symbol)
+	}
+	symbols = append(symbols, b.Symbols...)
+	symbols = append(symbols, b.fileSymbols...)
+	if b.BaseAsset != "" {
+		if b.QuoteAsset != "" {
+			symbols = append(symbols, b.BaseAsset+b.QuoteAsset)
+		}
+		for _, quote := range b.QuoteAssets {
+			symbols = append(symbols, b.BaseAsset+quote)
+		}
+	}
+
+	unique := make([]string, 0, len(symbols))
+	seen := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		if !seen[symbol] {
+			seen[symbol] = true
+			unique = append(unique, symbol)
+		}
+	}
+	return unique
+}
+
+// inverses returns the inverse symbols of the pairs given by base_asset
+// and the quote assets
+func (b *Binance) inverses() map[string]string {
+	inverses := make(map[string]string, len(b.QuoteAssets)+1)
+	if b.BaseAsset == "" {
+		return inverses
+	}
+	for _, quote := range append([]string{b.QuoteAsset}, b.QuoteAssets...) {
+		if quote != "" {
+			inverses[b.BaseAsset+quote] = quote + b.BaseAsset
+		}
+	}
+	return inverses
+}
+
+// resolvePairs creates the pairs of the dynamic selection or of the
+// configured symbols.
+func (b *Binance) resolvePairs() error {
+	if b.dynamic {
+		b.pairsQueried = time.Now()
+		return b.resolveSelection()
+	}
+	return b.resolveCandidates()
+}
+
+// resolveCandidates creates the pairs of the candidate symbols listed on the
+// exchange or listed inversely for pairs given by base and quote asset.
+func (b *Binance) resolveCandidates() error {
+	candidates := b.candidates()
+	inverses := b.inverses()
+	b.pairs = make([]*pair, 0, len(candidates))
+	for _, symbol := range candidates {
+		b.Log.Debugf("Verifying requested symbol %s", symbol)
+		info, err := b.symbolInfo(symbol)
+		var inverted bool
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.Code == codeInvalidSymbol {
+			// Many fiat pairs are only listed in one direction
+			inverse, found := inverses[symbol]
+			if !found || slices.Contains(candidates, inverse) {
+				b.Log.Warnf("Skipping symbol %s not listed on the exchange", symbol)
+				continue
+			}
+			info, err = b.symbolInfo(inverse)
+			if errors.As(err, &apiErr) && apiErr.Code == codeInvalidSymbol {
+				b.Log.Warnf("Skipping symbol %s not listed on the exchange in either direction", symbol)
+				continue
+			}
+			inverted = true
+		}
+		if err != nil {
+			return fmt.Errorf("verifying symbol %s failed: %w", symbol, err)
+		}
+		if inverted {
+			b.Log.Infof("Symbol %s not listed on the exchange, reporting the inverse price of %s", symbol, info.Symbol)
+		}
+		p, err := b.addPair(info)
+		if err != nil {
+			return err
+		}
+		if inverted {
+			p.invert()
+		}
+	}
+
+	if len(b.pairs) == 0 {
+		return errors.New("none of the requested symbols is listed on the exchange")
+	}
+	return nil
+}
+
+// resolveSelection creates the pairs of the dynamic selection from the
+// symbols file or the symbols currently listed on the exchange
+func (b *Binance) resolveSelection() error {
+	switch {
+	case b.SymbolsFile != "":
+		return b.resolveSymbolsFile()
+	case b.TopSymbolsByVolume > 0:
+		return b.resolveTopPairs()
+	}
+	return b.resolveAllPairs()
+}
+
+// resolveAllPairs creates pairs for all symbols currently trading on the
+// exchange and matching the selection
+func (b *Binance) resolveAllPairs() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var info exchangeInfo
+	if err := b.query(ctx, exchangeEndpoint, nil, exchangeInfoWeight, &info); err != nil {
+		return fmt.Errorf("querying exchange information failed: %w", err)
+	}
+
+	previous := b.pairs
+	b.pairs = make([]*pair, 0, len(info.Symbols))
+	for _, s := range info.Symbols {
+		if !b.selects(s) {
+			continue
+		}
+		if _, err := b.addPair(s); err != nil {
+			b.pairs = previous
+			return err
+		}
+	}
+	if len(b.pairs) == 0 {
+		b.pairs = previous
+		return errors.New("no symbols trading on the exchange matching the selection")
+	}
+	b.logPairChanges(previous)
+	return nil
+}
+
+// selects checks if the symbol belongs to the dynamic selection, i.e. if it
+// is trading, quoted in one of the quote assets if given and matching the
+// include but not the exclude patterns.
+func (b *Binance) selects(info symbolInfo) bool {
+	if info.Status != statusTrading {
+		b.Log.Debugf("Skipping symbol %s with status %s", info.Symbol, info.Status)
+		return false
+	}
+	if len(b.quotes) > 0 && !slices.Contains(b.quotes, info.QuoteAsset) {
+		return false
+	}
+	matches := func(re *regexp.Regexp) bool { return re.MatchString(info.Symbol) }
+	if len(b.symbolInclude) > 0 && !slices.ContainsFunc(b.symbolInclude, matches) {
+		return false
+	}
+	return !slices.ContainsFunc(b.symbolExclude, matches)
+}
+
+func (b *Binance) addPair(info symbolInfo) (*pair, error) {
+	p := newPair(info)
+	if b.futures() {
+		p.tags["market"] = b.Market
+		if info.ContractType != "" {
+			p.tags["contract_type"] = info.ContractType
+		}
+	}
+	if b.PriceBands {
+		var err error
+		if p.bands, err = percentPriceBands(info); err != nil {
+			return nil, err
+		}
+		if p.bands == nil {
+			b.Log.Warnf("Symbol %s has no percent-price filter, skipping price bands", info.Symbol)
+		}
+	}
+	b.pairs = append(b.pairs, p)
+	return p, nil
+}
+
+func (b *Binance) symbolInfo(symbol string) (symbolInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var info exchangeInfo
+	if err := b.query(ctx, exchangeEndpoint, url.Values{"symbol": {symbol}}, exchangeInfoWeight, &info); err != nil {
+		return symbolInfo{}, err
+	}
+	for _, s := range info.Symbols {
+		if s.Symbol == symbol {
+			return s, nil
+		}
+	}
+	return symbolInfo{}, &apiError{Code: codeInvalidSymbol, Msg: "Invalid symbol."}
+}
+
+// refreshPairs reselects the pairs of the dynamic selection once per
+// symbols_refresh interval, keeping the previous pairs on failure.
+func (b *Binance) refreshPairs() error {
+	if time.Since(b.pairsQueried) < time.Duration(b.SymbolsRefresh) {
+		return nil
+	}
+	b.pairsQueried = time.Now()
+
+	if err := b.resolveSelection(); err != nil {
+		return fmt.Errorf("refreshing symbols failed: %w", err)
+	}
+	if b.ConvertTo != "" {
+		return b.resolveConversions()
+	}
+	return nil
+}
+
+// logPairChanges logs the symbols added to and removed from the selection
+func (b *Binance) logPairChanges(previous []*pair) {
+	if previous == nil {
+		b.Log.Debugf("Collecting %d symbols", len(b.pairs))
+		return
+	}
+	symbols := func(pairs []*pair) []string {
+		s := make([]string, 0, len(pairs))
+		for _, p := range pairs {
+			s = append(s, p.symbol)
+		}
+		return s
+	}
+	before, after := symbols(previous), symbols(b.pairs)
+	for _, symbol := range after {
+		if !slices.Contains(before, symbol) {
+			b.Log.Infof("Adding symbol %s to the selection", symbol)
+		}
+	}
+	for _, symbol := range before {
+		if !slices.Contains(after, symbol) {
+			b.Log.Infof("Removing symbol %s from the selection", symbol)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package binance
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// loadSymbolsFile reads the symbols from the file or HTTP(S) URL given by
+// symbols_file. Symbols are separated by whitespace or commas and text after
+// a '#' is ignored as comment.
+func (b *Binance) loadSymbolsFile() ([]string, error) {
+	var r io.ReadCloser
+	if strings.HasPrefix(b.SymbolsFile, "http://") || strings.HasPrefix(b.SymbolsFile, "https://") {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.SymbolsFile, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := b.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get symbols from %s: %w", b.SymbolsFile, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to get symbols from %s: %s", b.SymbolsFile, resp.Status)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(b.SymbolsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read symbols: %w", err)
+		}
+		r = f
+	}
+	defer r.Close()
+
+	var symbols []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		for _, symbol := range strings.FieldsFunc(line, func(c rune) bool { return c == ',' || c == ' ' || c == '\t' }) {
+			symbols = append(symbols, strings.ToUpper(strings.TrimSpace(symbol)))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read symbols from %s: %w", b.SymbolsFile, err)
+	}
+	return symbols, nil
+}
+
+// resolveSymbolsFile (re)loads the symbols file and resolves the pairs if
+// the list of symbols changed, keeping the previous pairs on failure.
+func (b *Binance) resolveSymbolsFile() error {
+	symbols, err := b.loadSymbolsFile()
+	if err != nil {
+		return err
+	}
+	if b.pairs != nil && slices.Equal(symbols, b.fileSymbols) {
+		return nil
+	}
+
+	previous, previousSymbols := b.pairs, b.fileSymbols
+	b.fileSymbols = symbols
+	if err := b.resolveCandidates(); err != nil {
+		b.pairs, b.fileSymbols = previous, previousSymbols
+		return err
+	}
+	b.logPairChanges(previous)
+	return nil
+}
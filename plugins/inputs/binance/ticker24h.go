@@ -0,0 +1,130 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	ticker24hEndpoint string = "/ticker/24hr"
+	// Quote asset used as reference for the US dollar
+	usdQuote string = "USDT"
+)
+
+// ticker24hWeight returns the request weight of the rolling 24h statistics
+// for the given number of symbols, with zero requesting all symbols
+func ticker24hWeight(symbols int) int64 {
+	switch {
+	case symbols == 0 || symbols > 100:
+		return 80
+	case symbols > 20:
+		return 40
+	default:
+		return 2
+	}
+}
+
+// gather24hStats emits the price-change statistics of the rolling 24h window
+// for all pairs in a single request.
+func (b *Binance) gather24hStats(acc telegraf.Accumulator) error {
+	var params url.Values
+	var weight int64
+	// The futures API does not support selecting multiple symbols
+	if b.AllSymbols || b.futures() {
+		weight = ticker24hWeight(0)
+	} else {
+		symbols := make([]string, 0, len(b.pairs))
+		for _, p := range b.pairs {
+			symbols = append(symbols, p.symbol)
+		}
+		buf, err := json.Marshal(symbols)
+		if err != nil {
+			return err
+		}
+		params = url.Values{"symbols": {string(buf)}}
+		weight = ticker24hWeight(len(symbols))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var tickers []tickerStatistics
+	if err := b.query(ctx, ticker24hEndpoint, params, weight, &tickers); err != nil {
+		return err
+	}
+
+	var usdPrices map[string]float64
+	if b.NotionalUSD {
+		var err error
+		if usdPrices, err = b.usdPrices(ctx); err != nil {
+			acc.AddError(fmt.Errorf("querying USD prices failed: %w", err))
+		}
+	}
+
+	bySymbol := make(map[string]tickerStatistics, len(tickers))
+	for _, t := range tickers {
+		bySymbol[t.Symbol] = t
+	}
+	for _, p := range b.pairs {
+		t, found := bySymbol[p.symbol]
+		if !found {
+			acc.AddError(fmt.Errorf("no 24h statistics received for symbol %s", p.symbol))
+			continue
+		}
+		fields, err := t.fields()
+		if err != nil {
+			acc.AddError(fmt.Errorf("parsing 24h statistics of %s failed: %w", p.symbol, err))
+			continue
+		}
+		if price, found := usdPrices[p.tags["quote"]]; found {
+			if volume, ok := fields["quote_volume"].(float64); ok {
+				fields["notional_usd"] = volume * price
+			}
+		}
+		acc.AddFields("binance_24h", fields, p.tags)
+	}
+	return nil
+}
+
+// usdPrices returns the prices of all assets tradable against USDT in USDT,
+// using the inverse pair if an asset is only traded with USDT as quote, e.g.
+// USDTTRY.
+func (b *Binance) usdPrices(ctx context.Context) (map[string]float64, error) {
+	var ticks []tick
+	if err := b.query(ctx, priceEndpoint, nil, multiPriceWeight, &ticks); err != nil {
+		return nil, err
+	}
+	return pricesIn(ticks, usdQuote), nil
+}
+
+// pricesIn returns the prices of all assets tradable against the given quote
+// asset in the quote asset, using the inverse pair if an asset is only traded
+// with the quote asset as base.
+func pricesIn(ticks []tick, quote string) map[string]float64 {
+	prices := map[string]float64{quote: 1}
+	inverse := make(map[string]float64)
+	for _, t := range ticks {
+		price, err := strconv.ParseFloat(t.Price, 64)
+		if err != nil || price <= 0 {
+			continue
+		}
+		if asset, found := strings.CutSuffix(t.Symbol, quote); found && asset != "" {
+			prices[asset] = price
+		} else if asset, found := strings.CutPrefix(t.Symbol, quote); found && asset != "" {
+			inverse[asset] = 1 / price
+		}
+	}
+	for asset, price := range inverse {
+		if _, found := prices[asset]; !found {
+			prices[asset] = price
+		}
+	}
+	return prices
+}
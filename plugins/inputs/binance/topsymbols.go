@@ -0,0 +1,80 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// volumeCandidate is a trading symbol with its 24h quote volume in US dollars
+type volumeCandidate struct {
+	info   symbolInfo
+	volume float64
+}
+
+// resolveTopPairs selects the trading pairs with the highest 24h quote volume.
+// The volumes are compared in US dollars, so pairs with a quote asset not
+// tradable against USDT are never selected.
+func (b *Binance) resolveTopPairs() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var info exchangeInfo
+	if err := b.query(ctx, exchangeEndpoint, nil, exchangeInfoWeight, &info); err != nil {
+		return fmt.Errorf("querying exchange information failed: %w", err)
+	}
+	var tickers []tickerStatistics
+	params := url.Values{"type": {"MINI"}}
+	if err := b.query(ctx, ticker24hEndpoint, params, ticker24hWeight(0), &tickers); err != nil {
+		return fmt.Errorf("querying 24h statistics failed: %w", err)
+	}
+	usdPrices, err := b.usdPrices(ctx)
+	if err != nil {
+		return fmt.Errorf("querying USD prices failed: %w", err)
+	}
+
+	volumes := make(map[string]string, len(tickers))
+	for _, t := range tickers {
+		volumes[t.Symbol] = t.QuoteVolume
+	}
+	candidates := make([]volumeCandidate, 0, len(info.Symbols))
+	for _, s := range info.Symbols {
+		price, found := usdPrices[s.QuoteAsset]
+		if !found || !b.selects(s) {
+			continue
+		}
+		volume, err := strconv.ParseFloat(volumes[s.Symbol], 64)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, volumeCandidate{info: s, volume: volume * price})
+	}
+	slices.SortStableFunc(candidates, func(a, b volumeCandidate) int {
+		switch {
+		case a.volume > b.volume:
+			return -1
+		case a.volume < b.volume:
+			return 1
+		default:
+			return 0
+		}
+	})
+	candidates = candidates[:min(len(candidates), b.TopSymbolsByVolume)]
+	if len(candidates) == 0 {
+		return fmt.Errorf("no symbols trading on the exchange with a volume in %s", usdQuote)
+	}
+
+	previous := b.pairs
+	b.pairs = make([]*pair, 0, len(candidates))
+	for _, c := range candidates {
+		if _, err := b.addPair(c.info); err != nil {
+			b.pairs = previous
+			return err
+		}
+	}
+	b.logPairChanges(previous)
+	return nil
+}
@@ -0,0 +1,98 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName string = "github.com/influxdata/telegraf/plugins/inputs/binance"
+
+// initTracing sets up the tracer exporting the spans to the configured OTLP
+// endpoint or a no-op tracer if tracing is disabled.
+func (b *Binance) initTracing() error {
+	if b.spanExporter == nil && b.TracingEndpoint != "" {
+		exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(b.TracingEndpoint))
+		if err != nil {
+			return fmt.Errorf("creating trace exporter failed: %w", err)
+		}
+		b.spanExporter = exporter
+	}
+	if b.spanExporter == nil {
+		b.tracer = noop.NewTracerProvider().Tracer(tracerName)
+		b.traceCtx = context.Background()
+		return nil
+	}
+
+	b.tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(b.spanExporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName("telegraf"),
+			attribute.String("telegraf.plugin", "inputs.binance"),
+		)),
+	)
+	b.tracer = b.tracerProvider.Tracer(tracerName)
+	b.traceCtx = context.Background()
+	return nil
+}
+
+// startGatherSpan starts the span of a gather cycle becoming the parent of
+// the spans of all requests until the returned function is called.
+func (b *Binance) startGatherSpan() func() {
+	ctx, span := b.tracer.Start(context.Background(), "gather")
+	b.traceCtx = ctx
+	return func() {
+		span.End()
+		b.traceCtx = context.Background()
+	}
+}
+
+// stopTracing exports the pending spans and releases the exporter, limited
+// by the configured timeout.
+func (b *Binance) stopTracing() {
+	if b.tracerProvider == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+	if err := b.tracerProvider.Shutdown(ctx); err != nil {
+		b.Log.Errorf("Exporting trace spans failed: %v", err)
+	}
+	b.tracerProvider = nil
+}
+
+// startRequestSpan starts a span for the request as child of the current
+// gather cycle
+func (b *Binance) startRequestSpan(r *http.Request) trace.Span {
+	_, span := b.tracer.Start(b.traceCtx, r.Method+" "+r.URL.Path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.URLFull(redactURL(r.URL)),
+			semconv.ServerAddress(r.URL.Hostname()),
+		),
+	)
+	return span
+}
+
+// endRequestSpan records the outcome of the request and ends its span
+func endRequestSpan(span trace.Span, statusCode int, err error) {
+	if statusCode != 0 {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(statusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,194 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	historicalTradesEndpoint string = "/historicalTrades"
+	historicalTradesWeight   int64  = 25
+	// Maximum number of trades returned per request
+	historicalTradesLimit int = 1000
+
+	recentTradesEndpoint string = "/trades"
+	recentTradesWeight   int64  = 25
+)
+
+type trade struct {
+	ID           int64  `json:"id"`
+	Price        string `json:"price"`
+	Qty          string `json:"qty"`
+	QuoteQty     string `json:"quoteQty"`
+	Time         int64  `json:"time"`
+	IsBuyerMaker bool   `json:"isBuyerMaker"`
+}
+
+// gatherHistoricalTrades emits all trades of the pairs since the last
+// reported trade, paging through the trade history until either all trades
+// are collected or the rate-limit budget is exhausted.
+func (b *Binance) gatherHistoricalTrades(acc telegraf.Accumulator) {
+	for _, p := range b.pairs {
+		acc.AddError(b.gatherPairHistoricalTrades(acc, p))
+	}
+}
+
+func (b *Binance) gatherPairHistoricalTrades(acc telegraf.Accumulator, p *pair) error {
+	params := p.query()
+	params.Set("limit", strconv.Itoa(historicalTradesLimit))
+
+	for {
+		// Without a known last trade, start with the most recent trades
+		last, found := b.state.LastTradeID[p.symbol]
+		if found {
+			params.Set("fromId", strconv.FormatInt(last+1, 10))
+		}
+
+		address := withParams(b.apiURL+historicalTradesEndpoint, params)
+		if err := b.budget.reserve(time.Now(), historicalTradesWeight); err != nil {
+			b.Log.Debugf("Continuing historical trades of %s in the next gather cycle: %v", p.symbol, err)
+			return nil
+		}
+
+		trades, err := b.fetchHistoricalTrades(address)
+		if err != nil {
+			return err
+		}
+		for _, t := range trades {
+			if err := addTrade(acc, p, t); err != nil {
+				return err
+			}
+			b.state.LastTradeID[p.symbol] = t.ID
+		}
+
+		if len(trades) < historicalTradesLimit {
+			return nil
+		}
+	}
+}
+
+func (b *Binance) fetchHistoricalTrades(address string) ([]trade, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	r, err := newRequest(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var trades []trade
+	if err := b.doAuthenticated(r, &trades); err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+// gatherRecentTrades emits a summary of the most recent trades of the pairs
+func (b *Binance) gatherRecentTrades(acc telegraf.Accumulator) {
+	for _, p := range b.pairs {
+		acc.AddError(b.gatherPairRecentTrades(acc, p))
+	}
+}
+
+func (b *Binance) gatherPairRecentTrades(acc telegraf.Accumulator, p *pair) error {
+	params := p.query()
+	params.Set("limit", strconv.Itoa(b.RecentTrades))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+	var trades []trade
+	if err := b.query(ctx, recentTradesEndpoint, params, recentTradesWeight, &trades); err != nil {
+		return err
+	}
+
+	var summary tradeSummary
+	for _, t := range trades {
+		price, err := strconv.ParseFloat(t.Price, 64)
+		if err != nil {
+			return fmt.Errorf("cannot parse price %q of trade %d of %s: %w", t.Price, t.ID, p.symbol, err)
+		}
+		quantity, err := strconv.ParseFloat(t.Qty, 64)
+		if err != nil {
+			return fmt.Errorf("cannot parse quantity %q of trade %d of %s: %w", t.Qty, t.ID, p.symbol, err)
+		}
+		summary.add(price, quantity, t.IsBuyerMaker)
+	}
+
+	fields := summary.fields()
+	if len(trades) > 0 {
+		// Trades are returned in ascending order
+		fields["first_trade_time"] = trades[0].Time
+		fields["last_trade_time"] = trades[len(trades)-1].Time
+		fields["last_trade_id"] = trades[len(trades)-1].ID
+	}
+	acc.AddFields("binance_recent_trades", fields, p.tags)
+	return nil
+}
+
+// tradeSummary accumulates the volume and price range of trades
+type tradeSummary struct {
+	count       int
+	buyVolume   float64
+	sellVolume  float64
+	quoteVolume float64
+	minPrice    float64
+	maxPrice    float64
+}
+
+func (s *tradeSummary) add(price, quantity float64, isBuyerMaker bool) {
+	if s.count == 0 || price < s.minPrice {
+		s.minPrice = price
+	}
+	if s.count == 0 || price > s.maxPrice {
+		s.maxPrice = price
+	}
+	s.count++
+
+	// The taker sold if the buyer was the maker of the trade
+	if isBuyerMaker {
+		s.sellVolume += quantity
+	} else {
+		s.buyVolume += quantity
+	}
+	s.quoteVolume += price * quantity
+}
+
+func (s *tradeSummary) fields() map[string]interface{} {
+	fields := map[string]interface{}{"trade_count": s.count}
+	if s.count == 0 {
+		return fields
+	}
+	fields["buy_volume"] = s.buyVolume
+	fields["sell_volume"] = s.sellVolume
+	fields["min_price"] = s.minPrice
+	fields["max_price"] = s.maxPrice
+	if volume := s.buyVolume + s.sellVolume; volume > 0 {
+		fields["vwap"] = s.quoteVolume / volume
+	}
+	return fields
+}
+
+func addTrade(acc telegraf.Accumulator, p *pair, t trade) error {
+	fields := map[string]interface{}{"id": t.ID}
+	err := parseFloatFields(fields, map[string]string{
+		"price":          t.Price,
+		"quantity":       t.Qty,
+		"quote_quantity": t.QuoteQty,
+	})
+	if err != nil {
+		return fmt.Errorf("parsing trade %d of %s failed: %w", t.ID, p.symbol, err)
+	}
+
+	// The taker sold if the buyer was the maker of the trade
+	side := "buy"
+	if t.IsBuyerMaker {
+		side = "sell"
+	}
+	acc.AddFields("binance_trade", fields, p.tagsWith("side", side), time.UnixMilli(t.Time))
+	return nil
+}
@@ -0,0 +1,117 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	tradingDayEndpoint string = "/ticker/tradingDay"
+	// Request weight per symbol, the maximum number of symbols per request
+	// and the maximum weight of a single request
+	tradingDayWeight     int64 = 4
+	tradingDayMaxSymbols int   = 100
+	tradingDayMaxWeight  int64 = 200
+)
+
+// Time-zone offsets accepted by Binance, e.g. "0", "-1:00" or "05:45"
+var timezoneOffset = regexp.MustCompile(`^[+-]?\d{1,2}(:\d{2})?$`)
+
+// tickerStatistics are the price-change statistics of a symbol over a
+// trading day or a rolling window
+type tickerStatistics struct {
+	Symbol             string `json:"symbol"`
+	PriceChange        string `json:"priceChange"`
+	PriceChangePercent string `json:"priceChangePercent"`
+	WeightedAvgPrice   string `json:"weightedAvgPrice"`
+	OpenPrice          string `json:"openPrice"`
+	HighPrice          string `json:"highPrice"`
+	LowPrice           string `json:"lowPrice"`
+	LastPrice          string `json:"lastPrice"`
+	Volume             string `json:"volume"`
+	QuoteVolume        string `json:"quoteVolume"`
+	OpenTime           int64  `json:"openTime"`
+	CloseTime          int64  `json:"closeTime"`
+	Count              int64  `json:"count"`
+}
+
+func (t *tickerStatistics) fields() (map[string]interface{}, error) {
+	fields := map[string]interface{}{
+		"trades":     t.Count,
+		"open_time":  t.OpenTime,
+		"close_time": t.CloseTime,
+	}
+	err := parseFloatFields(fields, map[string]string{
+		"open":                 t.OpenPrice,
+		"high":                 t.HighPrice,
+		"low":                  t.LowPrice,
+		"close":                t.LastPrice,
+		"volume":               t.Volume,
+		"quote_volume":         t.QuoteVolume,
+		"price_change":         t.PriceChange,
+		"price_change_percent": t.PriceChangePercent,
+		"weighted_avg_price":   t.WeightedAvgPrice,
+	})
+	return fields, err
+}
+
+// gatherTradingDay emits the statistics of the current trading day in the
+// configured time-zone for all pairs.
+func (b *Binance) gatherTradingDay(acc telegraf.Accumulator) {
+	for start := 0; start < len(b.pairs); start += tradingDayMaxSymbols {
+		end := min(start+tradingDayMaxSymbols, len(b.pairs))
+		acc.AddError(b.gatherTradingDayBatch(acc, b.pairs[start:end]))
+	}
+}
+
+func (b *Binance) gatherTradingDayBatch(acc telegraf.Accumulator, pairs []*pair) error {
+	symbols := make([]string, 0, len(pairs))
+	for _, p := range pairs {
+		symbols = append(symbols, p.symbol)
+	}
+	buf, err := json.Marshal(symbols)
+	if err != nil {
+		return err
+	}
+	params := url.Values{
+		"symbols":  {string(buf)},
+		"timeZone": {b.TradingDayTimezone},
+		"type":     {"FULL"},
+	}
+	weight := min(tradingDayWeight*int64(len(symbols)), tradingDayMaxWeight)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	var tickers []tickerStatistics
+	if err := b.query(ctx, tradingDayEndpoint, params, weight, &tickers); err != nil {
+		return err
+	}
+
+	bySymbol := make(map[string]tickerStatistics, len(tickers))
+	for _, t := range tickers {
+		bySymbol[t.Symbol] = t
+	}
+	for _, p := range pairs {
+		t, found := bySymbol[p.symbol]
+		if !found {
+			acc.AddError(fmt.Errorf("no trading-day statistics received for symbol %s", p.symbol))
+			continue
+		}
+
+		fields, err := t.fields()
+		if err != nil {
+			acc.AddError(fmt.Errorf("parsing trading-day statistics of %s failed: %w", p.symbol, err))
+			continue
+		}
+
+		acc.AddFields("binance_trading_day", fields, p.tagsWith("timezone", b.TradingDayTimezone))
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	depositHistoryEndpoint    string = "/capital/deposit/hisrec"
+	withdrawalHistoryEndpoint string = "/capital/withdraw/history"
+	// Both endpoints are additionally limited per account
+	transferHistoryWeight int64 = 1
+	// Maximum number of records returned per request
+	transferHistoryLimit int = 1000
+	// Maximum time span of a history query
+	transferHistoryWindow = 89 * 24 * time.Hour
+	// Layout of the times of withdrawals in UTC
+	withdrawalTimeLayout = "2006-01-02 15:04:05"
+)
+
+var depositStatuses = map[int]string{
+	0: "pending",
+	1: "success",
+	2: "rejected",
+	6: "credited",
+	7: "wrong_deposit",
+	8: "waiting_user_confirm",
+}
+
+var withdrawalStatuses = map[int]string{
+	0: "email_sent",
+	1: "cancelled",
+	2: "awaiting_approval",
+	3: "rejected",
+	4: "processing",
+	5: "failure",
+	6: "completed",
+}
+
+type deposit struct {
+	ID         string `json:"id"`
+	Amount     string `json:"amount"`
+	Coin       string `json:"coin"`
+	Network    string `json:"network"`
+	Status     int    `json:"status"`
+	TxID       string `json:"txId"`
+	InsertTime int64  `json:"insertTime"`
+}
+
+type withdrawal struct {
+	ID             string `json:"id"`
+	Amount         string `json:"amount"`
+	TransactionFee string `json:"transactionFee"`
+	Coin           string `json:"coin"`
+	Network        string `json:"network"`
+	Status         int    `json:"status"`
+	TxID           string `json:"txId"`
+	ApplyTime      string `json:"applyTime"`
+}
+
+// Statuses after which deposits and withdrawals do not change anymore
+var (
+	depositFinalStatuses    = map[int]bool{1: true, 2: true, 7: true}
+	withdrawalFinalStatuses = map[int]bool{1: true, 3: true, 5: true, 6: true}
+)
+
+// pendingTransfer is a reported deposit or withdrawal not yet in a final
+// status
+type pendingTransfer struct {
+	Time   int64 `json:"time"`
+	Status int   `json:"status"`
+}
+
+// statusName returns the name of a transfer status or its code if unknown
+func statusName(names map[int]string, status int) string {
+	if name, found := names[status]; found {
+		return name
+	}
+	return strconv.Itoa(status)
+}
+
+// transferKey returns the key of a record of the transfer type in the state
+func transferKey(kind, id string) string {
+	return kind + "/" + id
+}
+
+// transferParams returns the query continuing after the last reported record
+// of the given transfer type, reaching back at most the maximum query window.
+// Pending records are queried again until they reach a final status; those
+// outside of the window are not tracked anymore.
+func (b *Binance) transferParams(kind string) url.Values {
+	earliest := time.Now().Add(-transferHistoryWindow).UnixMilli()
+	start := earliest
+	if last, found := b.state.LastTransfer[kind]; found {
+		start = max(start, last+1)
+	}
+	for key, pending := range b.state.PendingTransfer {
+		if !strings.HasPrefix(key, kind+"/") {
+			continue
+		}
+		if pending.Time < earliest {
+			delete(b.state.PendingTransfer, key)
+			continue
+		}
+		start = min(start, pending.Time)
+	}
+	return url.Values{
+		"startTime": {strconv.FormatInt(start, 10)},
+		"limit":     {strconv.Itoa(transferHistoryLimit)},
+	}
+}
+
+// queryTransfers pages through the history of the given transfer type,
+// calling fetch with the query of each page returning the number of records,
+// until a page is not full.
+func (b *Binance) queryTransfers(kind string, fetch func(ctx context.Context, params url.Values) (int, error)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	params := b.transferParams(kind)
+	for offset := 0; ; offset += transferHistoryLimit {
+		params.Set("offset", strconv.Itoa(offset))
+		n, err := fetch(ctx, params)
+		if err != nil {
+			return err
+		}
+		if n < transferHistoryLimit {
+			return nil
+		}
+	}
+}
+
+// reportTransfer checks whether the record of the given transfer type is new
+// or changed its status since it was reported, and tracks the record until
+// it reaches a final status.
+func (b *Binance) reportTransfer(kind, id string, t int64, status int, final bool) bool {
+	key := transferKey(kind, id)
+	pending, found := b.state.PendingTransfer[key]
+	if found && pending.Status == status {
+		return false
+	}
+	if !found && t <= b.state.LastTransfer[kind] {
+		return false
+	}
+	if final {
+		delete(b.state.PendingTransfer, key)
+	} else {
+		b.state.PendingTransfer[key] = pendingTransfer{Time: t, Status: status}
+	}
+	return true
+}
+
+// transferRecord is a parsed deposit or withdrawal
+type transferRecord struct {
+	id     string
+	time   int64
+	status int
+	final  bool
+	fields map[string]interface{}
+	tags   map[string]string
+}
+
+// addTransfers emits the new records and the status changes of pending ones of
+// the given transfer type and updates the state. All records are parsed
+// before, so a parse error leaves the state untouched and the whole window is
+// retried without duplicates.
+func (b *Binance) addTransfers(acc telegraf.Accumulator, kind string, records []transferRecord) {
+	last := b.state.LastTransfer[kind]
+	var reported int
+	for _, r := range records {
+		if !b.reportTransfer(kind, r.id, r.time, r.status, r.final) {
+			continue
+		}
+		acc.AddFields("binance_"+kind, r.fields, r.tags, time.UnixMilli(r.time))
+		last = max(last, r.time)
+		reported++
+	}
+	b.state.LastTransfer[kind] = last
+
+	acc.AddFields("binance_transfers", map[string]interface{}{"records": reported}, map[string]string{"type": kind})
+}
+
+// gatherDeposits emits the deposits to the account since the last reported one
+// and the status changes of pending deposits
+func (b *Binance) gatherDeposits(acc telegraf.Accumulator) error {
+	// Collect all pages before reporting to retry the whole window on errors
+	var deposits []deposit
+	err := b.queryTransfers("deposit", func(ctx context.Context, params url.Values) (int, error) {
+		var page []deposit
+		if err := b.querySigned(ctx, b.sapiURL+depositHistoryEndpoint, params, transferHistoryWeight, &page); err != nil {
+			return 0, err
+		}
+		deposits = append(deposits, page...)
+		return len(page), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	records := make([]transferRecord, 0, len(deposits))
+	for _, d := range deposits {
+		fields := map[string]interface{}{"id": d.ID, "tx_id": d.TxID}
+		if err := parseFloatFields(fields, map[string]string{"amount": d.Amount}); err != nil {
+			return fmt.Errorf("parsing deposit %s failed: %w", d.ID, err)
+		}
+		records = append(records, transferRecord{
+			id:     d.ID,
+			time:   d.InsertTime,
+			status: d.Status,
+			final:  depositFinalStatuses[d.Status],
+			fields: fields,
+			tags: map[string]string{
+				"coin":    d.Coin,
+				"network": d.Network,
+				"status":  statusName(depositStatuses, d.Status),
+			},
+		})
+	}
+	b.addTransfers(acc, "deposit", records)
+	return nil
+}
+
+// gatherWithdrawals emits the withdrawals from the account since the last
+// reported one and the status changes of pending withdrawals
+func (b *Binance) gatherWithdrawals(acc telegraf.Accumulator) error {
+	// Collect all pages before reporting to retry the whole window on errors
+	var withdrawals []withdrawal
+	err := b.queryTransfers("withdrawal", func(ctx context.Context, params url.Values) (int, error) {
+		var page []withdrawal
+		if err := b.querySigned(ctx, b.sapiURL+withdrawalHistoryEndpoint, params, transferHistoryWeight, &page); err != nil {
+			return 0, err
+		}
+		withdrawals = append(withdrawals, page...)
+		return len(page), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	records := make([]transferRecord, 0, len(withdrawals))
+	for _, w := range withdrawals {
+		applied, err := time.Parse(withdrawalTimeLayout, w.ApplyTime)
+		if err != nil {
+			return fmt.Errorf("cannot parse time %q of withdrawal %s: %w", w.ApplyTime, w.ID, err)
+		}
+		fields := map[string]interface{}{"id": w.ID, "tx_id": w.TxID}
+		err = parseFloatFields(fields, map[string]string{
+			"amount":          w.Amount,
+			"transaction_fee": w.TransactionFee,
+		})
+		if err != nil {
+			return fmt.Errorf("parsing withdrawal %s failed: %w", w.ID, err)
+		}
+		records = append(records, transferRecord{
+			id:     w.ID,
+			time:   applied.UnixMilli(),
+			status: w.Status,
+			final:  withdrawalFinalStatuses[w.Status],
+			fields: fields,
+			tags: map[string]string{
+				"coin":    w.Coin,
+				"network": w.Network,
+				"status":  statusName(withdrawalStatuses, w.Status),
+			},
+		})
+	}
+	b.addTransfers(acc, "withdrawal", records)
+	return nil
+}
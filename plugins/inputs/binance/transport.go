@@ -0,0 +1,133 @@
+package binance
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	netproxy "golang.org/x/net/proxy"
+)
+
+// newTransport creates the HTTP transport for all requests to Binance
+// honoring the proxy and dialer settings.
+func (b *Binance) newTransport() (*http.Transport, error) {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("unexpected type of default transport")
+	}
+	transport = transport.Clone()
+
+	// Disable the negotiation of HTTP/2 via ALPN
+	if b.ForceHTTP1 {
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	proxyFunc, err := b.HTTPProxy.Proxy()
+	if err != nil {
+		return nil, err
+	}
+	transport.Proxy = proxyFunc
+
+	// Settings of the default transport's dialer
+	dialer := &versionDialer{
+		dialer: &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		},
+		version: ipVersions[b.IPVersion],
+		cache:   b.dnsCache,
+	}
+	if b.LocalAddress != "" {
+		if dialer.dialer.LocalAddr, err = localAddress(b.LocalAddress, dialer.version); err != nil {
+			return nil, err
+		}
+	}
+	transport.DialContext = dialer.DialContext
+
+	if b.Socks5ProxyEnabled {
+		socksDialer, err := b.Socks5ProxyConfig.GetForwardingDialer(dialer)
+		if err != nil {
+			return nil, fmt.Errorf("creating socks5 proxy dialer failed: %w", err)
+		}
+		contextDialer, ok := socksDialer.(netproxy.ContextDialer)
+		if !ok {
+			return nil, errors.New("socks5 proxy dialer does not support contexts")
+		}
+		transport.Proxy = nil
+		transport.DialContext = contextDialer.DialContext
+	}
+	return transport, nil
+}
+
+// Suffixes of the network restricting connections to an IP version
+var ipVersions = map[string]string{
+	"any":  "",
+	"ipv4": "4",
+	"ipv6": "6",
+}
+
+// versionDialer restricts TCP connections to the given IP version, i.e. "4"
+// or "6", or allows both if empty. Hosts are resolved using the DNS cache if
+// given.
+type versionDialer struct {
+	dialer  *net.Dialer
+	version string
+	cache   *dnsCache
+}
+
+func (d *versionDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+func (d *versionDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if network == "tcp" {
+		network += d.version
+	}
+	if d.cache != nil {
+		return d.cache.dial(ctx, d.dialer, network, address, d.version)
+	}
+	return d.dialer.DialContext(ctx, network, address)
+}
+
+// isVersion checks if the IP address is of the given version
+func isVersion(ip net.IP, version string) bool {
+	switch version {
+	case "4":
+		return ip.To4() != nil
+	case "6":
+		return ip.To4() == nil
+	}
+	return true
+}
+
+// localAddress returns the address to bind outgoing connections to given
+// either as IP address or as name of a network interface. In the latter case
+// the first address of the interface with the given IP version is used.
+func localAddress(address, version string) (net.Addr, error) {
+	if ip := net.ParseIP(address); ip != nil {
+		if !isVersion(ip, version) {
+			return nil, fmt.Errorf("local_address %q does not match the IP version", address)
+		}
+		return &net.TCPAddr{IP: ip}, nil
+	}
+
+	iface, err := net.InterfaceByName(address)
+	if err != nil {
+		return nil, fmt.Errorf("local_address %q is neither an IP address nor a network interface", address)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("getting addresses of interface %q failed: %w", address, err)
+	}
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok && isVersion(ipnet.IP, version) {
+			return &net.TCPAddr{IP: ipnet.IP}, nil
+		}
+	}
+	return nil, fmt.Errorf("interface %q has no matching IP address", address)
+}
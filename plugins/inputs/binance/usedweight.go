@@ -0,0 +1,37 @@
+package binance
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/selfstat"
+)
+
+// Header of the request weight used by the IP address in the current minute
+const usedWeightHeader string = "X-Mbx-Used-Weight-1m"
+
+// recordUsedWeight keeps the request weight used by the IP address as
+// reported in the response headers of the given host.
+func (b *Binance) recordUsedWeight(host string, header http.Header) {
+	value := header.Get(usedWeightHeader)
+	if value == "" {
+		return
+	}
+	used, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		b.Log.Debugf("Cannot parse used weight %q reported by %s: %v", value, host, err)
+		return
+	}
+	selfstat.Register("binance", "used_weight_1m", map[string]string{"host": host}).Set(used)
+	b.usedWeights[host] = used
+}
+
+// addUsedWeights emits the request weight last reported by each host since
+// the last call
+func (b *Binance) addUsedWeights(acc telegraf.Accumulator) {
+	for host, used := range b.usedWeights {
+		acc.AddFields("binance_rate_limit", map[string]interface{}{"used_weight_1m": used}, map[string]string{"host": host})
+	}
+	clear(b.usedWeights)
+}
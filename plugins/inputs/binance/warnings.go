@@ -0,0 +1,136 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	// Status of symbols open for trading
+	statusTrading string = "TRADING"
+	// Pseudo status of pairs removed from the exchange information
+	statusDelisted string = "DELISTED"
+)
+
+// Delisting date in announcement titles, e.g. "Binance Will Delist ANT on 2024-02-20"
+var delistingDate = regexp.MustCompile(`\bon (\d{4}-\d{2}-\d{2})\b`)
+
+// gatherSymbolStatus refreshes the trading status and the filters of the
+// pairs. With symbol warnings enabled, it emits a warning for each pair whose
+// trading status changed since the last check, e.g. to "BREAK" for a trading
+// halt. Pairs not trading at all are reported on the first check. Pairs
+// removed from the exchange are reported as delisted and no longer collected.
+func (b *Binance) gatherSymbolStatus(acc telegraf.Accumulator) error {
+	if time.Since(b.statusQueried) < time.Duration(b.SymbolStatusRefresh) {
+		return nil
+	}
+	first := b.statusQueried.IsZero()
+	b.statusQueried = time.Now()
+
+	symbols := make([]string, 0, len(b.pairs))
+	for _, p := range b.pairs {
+		symbols = append(symbols, p.symbol)
+	}
+	buf, err := json.Marshal(symbols)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+	var info exchangeInfo
+	params := url.Values{"symbols": {string(buf)}}
+	err = b.query(ctx, exchangeEndpoint, params, exchangeInfoWeight, &info)
+	// Binance rejects the whole request if any of the symbols was removed, so
+	// find the removed ones in the full exchange information
+	var apiErr *apiError
+	delisted := errors.As(err, &apiErr) && apiErr.Code == codeInvalidSymbol
+	if delisted {
+		err = b.query(ctx, exchangeEndpoint, nil, exchangeInfoWeight, &info)
+	}
+	if err != nil {
+		return err
+	}
+	if delisted {
+		b.removeDelistedPairs(acc, info)
+	}
+
+	for _, s := range info.Symbols {
+		idx := slices.IndexFunc(b.pairs, func(p *pair) bool { return p.symbol == s.Symbol })
+		if idx < 0 {
+			continue
+		}
+		p := b.pairs[idx]
+		if b.SymbolFilters {
+			acc.AddError(addSymbolFilters(acc, p, s))
+		}
+
+		previous := p.status
+		p.status = s.Status
+		if !b.SymbolWarnings || previous == s.Status && !(first && s.Status != statusTrading) {
+			continue
+		}
+
+		fields := map[string]interface{}{
+			"status":          s.Status,
+			"previous_status": previous,
+			"trading":         s.Status == statusTrading,
+		}
+		acc.AddFields("binance_symbol_warning", fields, p.tagsWith("reason", "status"))
+	}
+	return nil
+}
+
+// removeDelistedPairs stops collecting the pairs missing in the given exchange
+// information and emits a warning for each with symbol warnings enabled.
+func (b *Binance) removeDelistedPairs(acc telegraf.Accumulator, info exchangeInfo) {
+	b.pairs = slices.DeleteFunc(b.pairs, func(p *pair) bool {
+		if slices.ContainsFunc(info.Symbols, func(s symbolInfo) bool { return s.Symbol == p.symbol }) {
+			return false
+		}
+		b.Log.Warnf("Symbol %s was removed from the exchange, no longer collecting it", p.symbol)
+		if b.SymbolWarnings {
+			fields := map[string]interface{}{
+				"status":          statusDelisted,
+				"previous_status": p.status,
+				"trading":         false,
+			}
+			acc.AddFields("binance_symbol_warning", fields, p.tagsWith("reason", "status"))
+		}
+		return true
+	})
+}
+
+// addDelistingWarnings emits a warning for each pair trading one of the
+// assets mentioned in the delisting announcement with the time left until
+// the delisting, if the announcement states a date.
+func (b *Binance) addDelistingWarnings(acc telegraf.Accumulator, id int64, title string, released time.Time) {
+	assets := assetsFromTitle(title)
+	var delisting time.Time
+	if m := delistingDate.FindStringSubmatch(title); m != nil {
+		// Delistings are announced by date only, assume the start of the day
+		delisting, _ = time.Parse(time.DateOnly, m[1])
+	}
+
+	for _, p := range b.pairs {
+		if !slices.Contains(assets, p.tags["base"]) && !slices.Contains(assets, p.tags["quote"]) {
+			continue
+		}
+		fields := map[string]interface{}{
+			"announcement_id": id,
+			"title":           title,
+		}
+		if !delisting.IsZero() {
+			fields["delisting_time"] = delisting.UnixMilli()
+			fields["lead_time"] = int64(delisting.Sub(released).Seconds())
+		}
+		acc.AddFields("binance_symbol_warning", fields, p.tagsWith("reason", "delisting"), released)
+	}
+}
@@ -0,0 +1,151 @@
+package binance_history
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+var errNotFound = errors.New("archive not found")
+
+// archivePath returns the path of the daily archive of the dataset, e.g.
+// "/data/spot/daily/klines/BTCUSDT/1m/BTCUSDT-1m-2024-01-01.zip"
+func (b *BinanceHistory) archivePath(p pair, dataset string, day time.Time) string {
+	date := day.Format(time.DateOnly)
+	if dataset == "klines" {
+		return fmt.Sprintf("/data/spot/daily/klines/%s/%s/%s-%s-%s.zip", p.symbol, b.KlineInterval, p.symbol, b.KlineInterval, date)
+	}
+	return fmt.Sprintf("/data/spot/daily/%s/%s/%s-%s-%s.zip", dataset, p.symbol, p.symbol, dataset, date)
+}
+
+// loadArchive downloads the daily archive of the dataset and emits all
+// records contained. It returns the number of records emitted.
+func (b *BinanceHistory) loadArchive(acc telegraf.Accumulator, p pair, dataset string, day time.Time) (int, error) {
+	address := b.URL + b.archivePath(p, dataset, day)
+
+	// Archives of trades are too large to be kept in memory
+	f, err := os.CreateTemp("", "binance_history_*.zip")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	checksum, err := b.download(address, f)
+	if err != nil {
+		return 0, err
+	}
+	if b.VerifyChecksum {
+		var expected strings.Builder
+		if _, err := b.download(address+".CHECKSUM", &expected); err != nil {
+			if errors.Is(err, errNotFound) {
+				return 0, fmt.Errorf("no checksum published for %s", address)
+			}
+			return 0, err
+		}
+		// The checksum file holds the hash followed by the file name
+		fields := strings.Fields(expected.String())
+		if len(fields) == 0 || !strings.EqualFold(fields[0], checksum) {
+			return 0, fmt.Errorf("checksum mismatch of %s", address)
+		}
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	archive, err := zip.NewReader(f, info.Size())
+	if err != nil {
+		return 0, fmt.Errorf("opening archive failed: %w", err)
+	}
+
+	var count int
+	for _, file := range archive.File {
+		n, err := b.loadFile(acc, p, dataset, file)
+		count += n
+		if err != nil {
+			return count, fmt.Errorf("reading %s failed: %w", file.Name, err)
+		}
+	}
+	return count, nil
+}
+
+// download writes the content of the address to the writer and returns the
+// hex-encoded SHA-256 checksum of the content
+func (b *BinanceHistory) download(address string, w io.Writer) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", errNotFound
+	default:
+		return "", fmt.Errorf("downloading %s failed: %s", address, resp.Status)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(w, hash), resp.Body); err != nil {
+		return "", fmt.Errorf("downloading %s failed: %w", address, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (b *BinanceHistory) loadFile(acc telegraf.Accumulator, p pair, dataset string, file *zip.File) (int, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	reader := csv.NewReader(rc)
+	reader.ReuseRecord = true
+	var count int
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+		// Some archives start with a header line
+		if line == 1 && isHeader(record) {
+			continue
+		}
+
+		switch dataset {
+		case "klines":
+			err = b.addKline(acc, p, record)
+		case "trades":
+			err = addTrade(acc, p, record)
+		case "aggTrades":
+			err = addAggTrade(acc, p, record)
+		}
+		if err != nil {
+			return count, fmt.Errorf("line %d: %w", line, err)
+		}
+		count++
+	}
+}
@@ -0,0 +1,203 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package binance_history
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/common/proxy"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+// Datasets available in the archives
+var datasets = []string{"klines", "trades", "aggTrades"}
+
+// Kline intervals available in the daily archives
+var klineIntervals = []string{"1s", "1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d"}
+
+// Recently ended days might not be published yet
+const publishDelay = 48 * time.Hour
+
+type BinanceHistory struct {
+	URL               string          `toml:"url"`
+	Pairs             []string        `toml:"pairs"`
+	Datasets          []string        `toml:"datasets"`
+	KlineInterval     string          `toml:"kline_interval"`
+	Start             string          `toml:"start"`
+	End               string          `toml:"end"`
+	ArchivesPerGather int             `toml:"archives_per_gather"`
+	VerifyChecksum    bool            `toml:"verify_checksum"`
+	Timeout           config.Duration `toml:"timeout"`
+	Log               telegraf.Logger `toml:"-"`
+	proxy.HTTPProxy
+
+	pairs  []pair
+	start  time.Time
+	end    time.Time
+	client *http.Client
+	state  state
+}
+
+// pair of assets traded as a symbol
+type pair struct {
+	base   string
+	quote  string
+	symbol string
+}
+
+// state of the plugin persisted across Telegraf runs
+type state struct {
+	// Last loaded day per dataset and symbol, e.g. "klines/BTCUSDT"
+	LastDay map[string]string `json:"last_day,omitempty"`
+}
+
+func (*BinanceHistory) SampleConfig() string {
+	return sampleConfig
+}
+
+func (b *BinanceHistory) Init() error {
+	if len(b.Pairs) == 0 {
+		return errors.New("no pairs configured")
+	}
+	for _, p := range b.Pairs {
+		base, quote, found := strings.Cut(strings.ToUpper(p), "/")
+		if !found || base == "" || quote == "" {
+			return fmt.Errorf("invalid pair %q, expected \"<base>/<quote>\"", p)
+		}
+		b.pairs = append(b.pairs, pair{base: base, quote: quote, symbol: base + quote})
+	}
+
+	if len(b.Datasets) == 0 {
+		b.Datasets = []string{"klines"}
+	}
+	for _, d := range b.Datasets {
+		if !slices.Contains(datasets, d) {
+			return fmt.Errorf("invalid dataset %q", d)
+		}
+	}
+	if !slices.Contains(klineIntervals, b.KlineInterval) {
+		return fmt.Errorf("invalid kline interval %q", b.KlineInterval)
+	}
+
+	if b.Start == "" {
+		return errors.New("start required")
+	}
+	var err error
+	if b.start, err = time.Parse(time.DateOnly, b.Start); err != nil {
+		return fmt.Errorf("invalid start %q: %w", b.Start, err)
+	}
+	if b.End != "" {
+		if b.end, err = time.Parse(time.DateOnly, b.End); err != nil {
+			return fmt.Errorf("invalid end %q: %w", b.End, err)
+		}
+		if b.end.Before(b.start) {
+			return fmt.Errorf("end %s before start %s", b.End, b.Start)
+		}
+	}
+
+	if b.ArchivesPerGather < 1 {
+		return fmt.Errorf("archives_per_gather %d must be at least one", b.ArchivesPerGather)
+	}
+
+	proxyFunc, err := b.HTTPProxy.Proxy()
+	if err != nil {
+		return err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFunc
+	b.client = &http.Client{Transport: transport}
+
+	b.state = state{LastDay: make(map[string]string)}
+	return nil
+}
+
+func (b *BinanceHistory) Gather(acc telegraf.Accumulator) error {
+	// Archives are published after the day ended in UTC
+	last := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	if !b.end.IsZero() && b.end.Before(last) {
+		last = b.end
+	}
+
+	for _, p := range b.pairs {
+		for _, dataset := range b.Datasets {
+			acc.AddError(b.load(acc, p, dataset, last))
+		}
+	}
+	return nil
+}
+
+// load emits the records of the next archives of the dataset not loaded yet
+// up to the given day
+func (b *BinanceHistory) load(acc telegraf.Accumulator, p pair, dataset string, last time.Time) error {
+	key := dataset + "/" + p.symbol
+	day := b.start
+	if done, found := b.state.LastDay[key]; found {
+		t, err := time.Parse(time.DateOnly, done)
+		if err != nil {
+			return fmt.Errorf("invalid last day %q of %s: %w", done, key, err)
+		}
+		if next := t.AddDate(0, 0, 1); next.After(day) {
+			day = next
+		}
+	}
+
+	// Days without archive count as well to bound the number of requests per
+	// gather cycle, e.g. for a start long before the listing of a symbol
+	for probed := 0; probed < b.ArchivesPerGather && !day.After(last); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		count, err := b.loadArchive(acc, p, dataset, day)
+		switch {
+		case errors.Is(err, errNotFound):
+			// Retry days possibly not published yet in the next gather cycle,
+			// earlier days are not available e.g. before the listing
+			if time.Since(day) < publishDelay {
+				return nil
+			}
+			b.Log.Debugf("No %s archive of %s available for %s", dataset, p.symbol, date)
+		case err != nil:
+			return fmt.Errorf("loading %s of %s for %s failed: %w", dataset, p.symbol, date, err)
+		default:
+			b.Log.Debugf("Loaded %d %s of %s for %s", count, dataset, p.symbol, date)
+		}
+		probed++
+		b.state.LastDay[key] = date
+	}
+	return nil
+}
+
+func (b *BinanceHistory) GetState() interface{} {
+	return b.state
+}
+
+func (b *BinanceHistory) SetState(s interface{}) error {
+	restored, ok := s.(state)
+	if !ok {
+		return fmt.Errorf("state has wrong type %T", s)
+	}
+	for key, day := range restored.LastDay {
+		b.state.LastDay[key] = day
+	}
+	return nil
+}
+
+func init() {
+	inputs.Add("binance_history", func() telegraf.Input {
+		return &BinanceHistory{
+			URL:               "https://data.binance.vision",
+			KlineInterval:     "1m",
+			ArchivesPerGather: 1,
+			VerifyChecksum:    true,
+			Timeout:           config.Duration(5 * time.Minute),
+		}
+	})
+}
@@ -0,0 +1,344 @@
+package binance_history
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+// newTestServer serves the CSV files in testdata as zipped archives with
+// their checksums
+func newTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := path.Base(r.URL.Path)
+		archive, checksum := strings.CutSuffix(name, ".CHECKSUM")
+
+		buf, err := os.ReadFile(filepath.Join("testdata", strings.TrimSuffix(archive, ".zip")+".csv"))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var zipped bytes.Buffer
+		zw := zip.NewWriter(&zipped)
+		f, err := zw.Create(strings.TrimSuffix(archive, ".zip") + ".csv")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			t.Error(err)
+			return
+		}
+		if _, err := f.Write(buf); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			t.Error(err)
+			return
+		}
+		if err := zw.Close(); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			t.Error(err)
+			return
+		}
+
+		content := zipped.Bytes()
+		if checksum {
+			hash := sha256.Sum256(content)
+			content = []byte(hex.EncodeToString(hash[:]) + "  " + archive + "\n")
+		}
+		if _, err := w.Write(content); err != nil {
+			t.Error(err)
+		}
+	}))
+}
+
+func newTestPlugin(url string) *BinanceHistory {
+	return &BinanceHistory{
+		URL:               url,
+		Pairs:             []string{"BTC/USDT"},
+		KlineInterval:     "1m",
+		ArchivesPerGather: 1,
+		VerifyChecksum:    true,
+		Timeout:           config.Duration(5 * time.Second),
+		Log:               testutil.Logger{},
+	}
+}
+
+func TestInitInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*BinanceHistory)
+		message string
+	}{
+		{
+			name:    "invalid pair",
+			modify:  func(b *BinanceHistory) { b.Pairs = []string{"BTCUSDT"} },
+			message: `invalid pair "BTCUSDT", expected "<base>/<quote>"`,
+		},
+		{
+			name:    "invalid dataset",
+			modify:  func(b *BinanceHistory) { b.Datasets = []string{"bookTicker"} },
+			message: `invalid dataset "bookTicker"`,
+		},
+		{
+			name:    "invalid interval",
+			modify:  func(b *BinanceHistory) { b.KlineInterval = "1w" },
+			message: `invalid kline interval "1w"`,
+		},
+		{
+			name:    "missing start",
+			modify:  func(b *BinanceHistory) { b.Start = "" },
+			message: "start required",
+		},
+		{
+			name:    "end before start",
+			modify:  func(b *BinanceHistory) { b.End = "2023-12-31" },
+			message: "end 2023-12-31 before start 2024-01-01",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := newTestPlugin("")
+			plugin.Start = "2024-01-01"
+			tt.modify(plugin)
+			require.EqualError(t, plugin.Init(), tt.message)
+		})
+	}
+}
+
+func TestKlines(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.Start = "2024-01-01"
+	plugin.End = "2024-01-02"
+	require.NoError(t, plugin.Init())
+
+	// Load one archive per gather cycle
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	tags := map[string]string{"base": "BTC", "quote": "USDT", "interval": "1m"}
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_kline",
+			tags,
+			map[string]interface{}{
+				"open":                   42283.58,
+				"high":                   42298.62,
+				"low":                    42261.02,
+				"close":                  42298.61,
+				"volume":                 35.92724,
+				"quote_volume":           1519032.5093849,
+				"trades":                 int64(1327),
+				"taker_buy_volume":       19.57335,
+				"taker_buy_quote_volume": 827488.0837241,
+			},
+			time.UnixMilli(1704067200000),
+		),
+		metric.New(
+			"binance_kline",
+			tags,
+			map[string]interface{}{
+				"open":                   42298.62,
+				"high":                   42320.0,
+				"low":                    42298.61,
+				"close":                  42320.0,
+				"volume":                 21.05318,
+				"quote_volume":           890815.7791627,
+				"trades":                 int64(1056),
+				"taker_buy_volume":       15.35237,
+				"taker_buy_quote_volume": 649562.0244717,
+			},
+			time.UnixMilli(1704067260000),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Len(t, acc.GetTelegrafMetrics(), 1)
+	require.Equal(t, "2024-01-02", plugin.state.LastDay["klines/BTCUSDT"])
+
+	// The range is loaded completely
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
+
+func TestTrades(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.Datasets = []string{"trades"}
+	plugin.Start = "2025-01-01"
+	plugin.End = "2025-01-01"
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	// Timestamps are given in microseconds since 2025
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_trade",
+			map[string]string{"base": "BTC", "quote": "USDT", "side": "sell"},
+			map[string]interface{}{
+				"id":             int64(4324719473),
+				"price":          93576.0,
+				"quantity":       0.0001,
+				"quote_quantity": 9.3576,
+			},
+			time.UnixMicro(1735689600012345),
+		),
+		metric.New(
+			"binance_trade",
+			map[string]string{"base": "BTC", "quote": "USDT", "side": "buy"},
+			map[string]interface{}{
+				"id":             int64(4324719474),
+				"price":          93576.01,
+				"quantity":       0.04,
+				"quote_quantity": 3743.0404,
+			},
+			time.UnixMicro(1735689600098765),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestAggTrades(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.Datasets = []string{"aggTrades"}
+	plugin.Start = "2024-01-01"
+	plugin.End = "2024-01-01"
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	// The header line is skipped
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_agg_trade",
+			map[string]string{"base": "BTC", "quote": "USDT", "side": "buy"},
+			map[string]interface{}{
+				"id":             int64(2999665025),
+				"price":          42283.58,
+				"quantity":       0.002,
+				"first_trade_id": int64(3347422211),
+				"last_trade_id":  int64(3347422212),
+			},
+			time.UnixMilli(1704067200005),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestMissingArchives(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	// Days without archive are skipped but count towards the archives per
+	// gather cycle
+	plugin := newTestPlugin(server.URL)
+	plugin.Start = "2023-12-30"
+	plugin.End = "2024-01-02"
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	for _, day := range []string{"2023-12-30", "2023-12-31"} {
+		require.NoError(t, plugin.Gather(&acc))
+		require.Empty(t, acc.Errors)
+		require.Empty(t, acc.GetTelegrafMetrics())
+		require.Equal(t, day, plugin.state.LastDay["klines/BTCUSDT"])
+	}
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Len(t, acc.GetTelegrafMetrics(), 2)
+	require.Equal(t, "2024-01-01", plugin.state.LastDay["klines/BTCUSDT"])
+
+	plugin = newTestPlugin(server.URL)
+	plugin.Start = "2023-12-30"
+	plugin.End = "2024-01-02"
+	plugin.ArchivesPerGather = 3
+	require.NoError(t, plugin.Init())
+
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+	require.Len(t, acc.GetTelegrafMetrics(), 2)
+	require.Equal(t, "2024-01-01", plugin.state.LastDay["klines/BTCUSDT"])
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	checksums := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".CHECKSUM") {
+			if _, err := w.Write([]byte("0123456789abcdef  BTCUSDT-1m-2024-01-01.zip\n")); err != nil {
+				t.Error(err)
+			}
+			return
+		}
+		http.Redirect(w, r, server.URL+r.URL.Path, http.StatusFound)
+	}))
+	defer checksums.Close()
+
+	plugin := newTestPlugin(checksums.URL)
+	plugin.Start = "2024-01-01"
+	plugin.End = "2024-01-01"
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Len(t, acc.Errors, 1)
+	require.ErrorContains(t, acc.Errors[0], "checksum mismatch")
+	require.Empty(t, acc.GetTelegrafMetrics())
+
+	// The archive is retried in the next gather cycle
+	require.Empty(t, plugin.state.LastDay)
+}
+
+func TestState(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	plugin := newTestPlugin(server.URL)
+	plugin.Start = "2024-01-01"
+	plugin.End = "2024-01-02"
+	require.NoError(t, plugin.Init())
+
+	// Pretend the first day was loaded in a previous run
+	require.NoError(t, plugin.SetState(state{LastDay: map[string]string{"klines/BTCUSDT": "2024-01-01"}}))
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Gather(&acc))
+	require.Empty(t, acc.Errors)
+
+	metrics := acc.GetTelegrafMetrics()
+	require.Len(t, metrics, 1)
+	require.Equal(t, time.UnixMilli(1704153600000), metrics[0].Time())
+}
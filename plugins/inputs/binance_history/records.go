@@ -0,0 +1,173 @@
+package binance_history
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// Timestamps above this value are given in microseconds instead of
+// milliseconds, as in the spot archives since 2025
+const microsecondsThreshold int64 = 1e14
+
+// isHeader checks if the record is a header line instead of data
+func isHeader(record []string) bool {
+	if len(record) == 0 {
+		return false
+	}
+	_, err := strconv.ParseInt(record[0], 10, 64)
+	return err != nil
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot parse timestamp %q: %w", s, err)
+	}
+	if v > microsecondsThreshold {
+		return time.UnixMicro(v), nil
+	}
+	return time.UnixMilli(v), nil
+}
+
+func parseFloatFields(fields map[string]interface{}, record []string, columns map[int]string) error {
+	for i, name := range columns {
+		v, err := strconv.ParseFloat(record[i], 64)
+		if err != nil {
+			return fmt.Errorf("cannot parse %s %q: %w", name, record[i], err)
+		}
+		fields[name] = v
+	}
+	return nil
+}
+
+func parseIntFields(fields map[string]interface{}, record []string, columns map[int]string) error {
+	for i, name := range columns {
+		v, err := strconv.ParseInt(record[i], 10, 64)
+		if err != nil {
+			return fmt.Errorf("cannot parse %s %q: %w", name, record[i], err)
+		}
+		fields[name] = v
+	}
+	return nil
+}
+
+// side of the taker, who sold if the buyer was the maker of the trade
+func side(isBuyerMaker string) string {
+	if strings.EqualFold(isBuyerMaker, "true") {
+		return "sell"
+	}
+	return "buy"
+}
+
+// addKline emits a kline record, i.e. open time, open, high, low, close,
+// volume, close time, quote volume, trades, taker buy volume, taker buy quote
+// volume and an ignored column
+func (b *BinanceHistory) addKline(acc telegraf.Accumulator, p pair, record []string) error {
+	if len(record) < 11 {
+		return fmt.Errorf("invalid kline with %d columns", len(record))
+	}
+	ts, err := parseTimestamp(record[0])
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string]interface{}, 9)
+	if err := parseIntFields(fields, record, map[int]string{8: "trades"}); err != nil {
+		return err
+	}
+	err = parseFloatFields(fields, record, map[int]string{
+		1:  "open",
+		2:  "high",
+		3:  "low",
+		4:  "close",
+		5:  "volume",
+		7:  "quote_volume",
+		9:  "taker_buy_volume",
+		10: "taker_buy_quote_volume",
+	})
+	if err != nil {
+		return err
+	}
+
+	tags := map[string]string{
+		"base":     p.base,
+		"quote":    p.quote,
+		"interval": b.KlineInterval,
+	}
+	acc.AddFields("binance_kline", fields, tags, ts)
+	return nil
+}
+
+// addTrade emits a trade record, i.e. id, price, quantity, quote quantity,
+// time, buyer is maker and best match
+func addTrade(acc telegraf.Accumulator, p pair, record []string) error {
+	if len(record) < 6 {
+		return fmt.Errorf("invalid trade with %d columns", len(record))
+	}
+	ts, err := parseTimestamp(record[4])
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string]interface{}, 4)
+	if err := parseIntFields(fields, record, map[int]string{0: "id"}); err != nil {
+		return err
+	}
+	err = parseFloatFields(fields, record, map[int]string{
+		1: "price",
+		2: "quantity",
+		3: "quote_quantity",
+	})
+	if err != nil {
+		return err
+	}
+
+	tags := map[string]string{
+		"base":  p.base,
+		"quote": p.quote,
+		"side":  side(record[5]),
+	}
+	acc.AddFields("binance_trade", fields, tags, ts)
+	return nil
+}
+
+// addAggTrade emits an aggregate trade record, i.e. id, price, quantity,
+// first trade id, last trade id, time, buyer is maker and best match
+func addAggTrade(acc telegraf.Accumulator, p pair, record []string) error {
+	if len(record) < 7 {
+		return fmt.Errorf("invalid aggregate trade with %d columns", len(record))
+	}
+	ts, err := parseTimestamp(record[5])
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string]interface{}, 5)
+	err = parseIntFields(fields, record, map[int]string{
+		0: "id",
+		3: "first_trade_id",
+		4: "last_trade_id",
+	})
+	if err != nil {
+		return err
+	}
+	err = parseFloatFields(fields, record, map[int]string{
+		1: "price",
+		2: "quantity",
+	})
+	if err != nil {
+		return err
+	}
+
+	tags := map[string]string{
+		"base":  p.base,
+		"quote": p.quote,
+		"side":  side(record[6]),
+	}
+	acc.AddFields("binance_agg_trade", fields, tags, ts)
+	return nil
+}
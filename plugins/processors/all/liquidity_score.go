@@ -0,0 +1,5 @@
+//go:build !custom || processors || processors.liquidity_score
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/liquidity_score" // register plugin
@@ -0,0 +1,5 @@
+//go:build !custom || processors || processors.venue_premium
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/processors/venue_premium" // register plugin
@@ -0,0 +1,105 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package liquidity_score
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+type LiquidityScore struct {
+	Field      string          `toml:"field"`
+	Components []*component    `toml:"component"`
+	Log        telegraf.Logger `toml:"-"`
+
+	totalWeight float64
+}
+
+type component struct {
+	Field         string  `toml:"field"`
+	Reference     float64 `toml:"reference"`
+	Weight        float64 `toml:"weight"`
+	LowerIsBetter bool    `toml:"lower_is_better"`
+}
+
+// score of the value relative to the reference between 0 and 1
+func (c *component) score(value float64) float64 {
+	var s float64
+	if c.LowerIsBetter {
+		if value <= 0 {
+			return 1
+		}
+		s = c.Reference / value
+	} else {
+		s = value / c.Reference
+	}
+	return max(0, min(s, 1))
+}
+
+func (*LiquidityScore) SampleConfig() string {
+	return sampleConfig
+}
+
+func (l *LiquidityScore) Init() error {
+	if l.Field == "" {
+		return errors.New("field required")
+	}
+	if len(l.Components) == 0 {
+		return errors.New("no components configured")
+	}
+	l.totalWeight = 0
+	for _, c := range l.Components {
+		if c.Field == "" {
+			return errors.New("component field required")
+		}
+		if c.Reference <= 0 {
+			return fmt.Errorf("reference %v of component %q must be positive", c.Reference, c.Field)
+		}
+		if c.Weight < 0 {
+			return fmt.Errorf("weight %v of component %q must not be negative", c.Weight, c.Field)
+		}
+		l.totalWeight += c.Weight
+	}
+	if l.totalWeight == 0 {
+		return errors.New("weights of the components sum up to zero")
+	}
+	return nil
+}
+
+func (l *LiquidityScore) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	for _, m := range in {
+		var sum float64
+		complete := true
+		for _, c := range l.Components {
+			raw, found := m.GetField(c.Field)
+			if !found {
+				complete = false
+				break
+			}
+			value, err := internal.ToFloat64(raw)
+			if err != nil {
+				l.Log.Errorf("Converting field %q of %q failed: %v", c.Field, m.Name(), err)
+				complete = false
+				break
+			}
+			sum += c.Weight * c.score(value)
+		}
+		if complete {
+			m.AddField(l.Field, sum/l.totalWeight*100)
+		}
+	}
+	return in
+}
+
+func init() {
+	processors.Add("liquidity_score", func() telegraf.Processor {
+		return &LiquidityScore{Field: "liquidity_score"}
+	})
+}
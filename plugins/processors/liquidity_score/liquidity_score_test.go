@@ -0,0 +1,93 @@
+package liquidity_score
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func newPlugin() *LiquidityScore {
+	return &LiquidityScore{
+		Field: "liquidity_score",
+		Components: []*component{
+			{Field: "spread_bps", Reference: 1, Weight: 0.5, LowerIsBetter: true},
+			{Field: "bid_notional_10bps", Reference: 100000, Weight: 0.25},
+			{Field: "ask_notional_10bps", Reference: 100000, Weight: 0.25},
+		},
+		Log: testutil.Logger{},
+	}
+}
+
+func TestInitInvalid(t *testing.T) {
+	plugin := newPlugin()
+	plugin.Components = nil
+	require.EqualError(t, plugin.Init(), "no components configured")
+
+	plugin = newPlugin()
+	plugin.Components[1].Reference = 0
+	require.EqualError(t, plugin.Init(), `reference 0 of component "bid_notional_10bps" must be positive`)
+
+	plugin = newPlugin()
+	for _, c := range plugin.Components {
+		c.Weight = 0
+	}
+	require.EqualError(t, plugin.Init(), "weights of the components sum up to zero")
+}
+
+func TestScore(t *testing.T) {
+	plugin := newPlugin()
+	require.NoError(t, plugin.Init())
+
+	input := []telegraf.Metric{
+		metric.New(
+			"binance_book",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{
+				"spread_bps":         2.0,
+				"bid_notional_10bps": 50000.0,
+				"ask_notional_10bps": 200000.0,
+			},
+			time.Unix(1741735124, 0),
+		),
+		// Metrics missing a component are passed without score
+		metric.New(
+			"binance_book",
+			map[string]string{"base": "ETH", "quote": "EUR"},
+			map[string]interface{}{
+				"spread_bps":         2.0,
+				"bid_notional_10bps": 50000.0,
+			},
+			time.Unix(1741735124, 0),
+		),
+	}
+	expected := []telegraf.Metric{
+		metric.New(
+			"binance_book",
+			map[string]string{"base": "BTC", "quote": "EUR"},
+			map[string]interface{}{
+				"spread_bps":         2.0,
+				"bid_notional_10bps": 50000.0,
+				"ask_notional_10bps": 200000.0,
+				"liquidity_score":    62.5,
+			},
+			time.Unix(1741735124, 0),
+		),
+		metric.New(
+			"binance_book",
+			map[string]string{"base": "ETH", "quote": "EUR"},
+			map[string]interface{}{
+				"spread_bps":         2.0,
+				"bid_notional_10bps": 50000.0,
+			},
+			time.Unix(1741735124, 0),
+		),
+	}
+
+	actual := plugin.Apply(input...)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
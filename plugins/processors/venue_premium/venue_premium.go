@@ -0,0 +1,190 @@
+//go:generate ../../../tools/readme_config_includer/generator
+package venue_premium
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+//go:embed sample.conf
+var sampleConfig string
+
+type VenuePremium struct {
+	AssetTag   string          `toml:"asset_tag"`
+	MetricName string          `toml:"metric_name"`
+	MaxAge     config.Duration `toml:"max_age"`
+	Domestic   *source         `toml:"domestic"`
+	Foreign    *source         `toml:"foreign"`
+	FX         *source         `toml:"fx"`
+	Log        telegraf.Logger `toml:"-"`
+
+	domestic map[string]price
+	foreign  map[string]price
+	fx       *price
+}
+
+// source of a price selected by metric name, field and tags
+type source struct {
+	Metric string            `toml:"metric"`
+	Field  string            `toml:"field"`
+	Tags   map[string]string `toml:"tags"`
+}
+
+type price struct {
+	value float64
+	time  time.Time
+}
+
+func (s *source) init(name string) error {
+	if s == nil {
+		return fmt.Errorf("%s price source required", name)
+	}
+	if s.Metric == "" || s.Field == "" {
+		return fmt.Errorf("metric and field of the %s price source required", name)
+	}
+	return nil
+}
+
+// price of the source in the metric, if the metric is part of the source
+func (s *source) price(m telegraf.Metric) (price, bool, error) {
+	if m.Name() != s.Metric {
+		return price{}, false, nil
+	}
+	for k, v := range s.Tags {
+		if tv, found := m.GetTag(k); !found || tv != v {
+			return price{}, false, nil
+		}
+	}
+	raw, found := m.GetField(s.Field)
+	if !found {
+		return price{}, false, nil
+	}
+	v, err := internal.ToFloat64(raw)
+	if err != nil {
+		return price{}, false, fmt.Errorf("converting field %q of %q failed: %w", s.Field, s.Metric, err)
+	}
+	return price{value: v, time: m.Time()}, true, nil
+}
+
+func (*VenuePremium) SampleConfig() string {
+	return sampleConfig
+}
+
+func (p *VenuePremium) Init() error {
+	if p.AssetTag == "" {
+		return errors.New("asset_tag required")
+	}
+	if err := p.Domestic.init("domestic"); err != nil {
+		return err
+	}
+	if err := p.Foreign.init("foreign"); err != nil {
+		return err
+	}
+	if p.FX != nil {
+		if err := p.FX.init("fx"); err != nil {
+			return err
+		}
+	}
+
+	p.domestic = make(map[string]price)
+	p.foreign = make(map[string]price)
+	return nil
+}
+
+func (p *VenuePremium) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	out := in
+	for _, m := range in {
+		if p.FX != nil {
+			if rate, ok, err := p.FX.price(m); err != nil {
+				p.Log.Error(err)
+			} else if ok {
+				p.fx = &rate
+			}
+		}
+
+		asset, found := m.GetTag(p.AssetTag)
+		if !found {
+			continue
+		}
+		var updated bool
+		if v, ok, err := p.Domestic.price(m); err != nil {
+			p.Log.Error(err)
+		} else if ok {
+			p.domestic[asset] = v
+			updated = true
+		}
+		if v, ok, err := p.Foreign.price(m); err != nil {
+			p.Log.Error(err)
+		} else if ok {
+			p.foreign[asset] = v
+			updated = true
+		}
+
+		if updated {
+			if premium := p.premium(asset, m.Time()); premium != nil {
+				out = append(out, premium)
+			}
+		}
+	}
+	return out
+}
+
+// premium metric of the asset if prices of all sources are available and
+// recent enough at the given time
+func (p *VenuePremium) premium(asset string, t time.Time) telegraf.Metric {
+	domestic, found := p.domestic[asset]
+	if !found || p.expired(domestic, t) {
+		return nil
+	}
+	foreign, found := p.foreign[asset]
+	if !found || p.expired(foreign, t) || foreign.value == 0 {
+		return nil
+	}
+
+	fields := map[string]interface{}{
+		"domestic_price": domestic.value,
+		"foreign_price":  foreign.value,
+	}
+	converted := foreign.value
+	if p.FX != nil {
+		if p.fx == nil || p.expired(*p.fx, t) || p.fx.value == 0 {
+			return nil
+		}
+		converted *= p.fx.value
+		fields["fx_rate"] = p.fx.value
+	}
+	fields["premium_percent"] = (domestic.value/converted - 1) * 100
+
+	tags := map[string]string{
+		"asset":    asset,
+		"domestic": p.Domestic.Metric,
+		"foreign":  p.Foreign.Metric,
+	}
+	return metric.New(p.MetricName, tags, fields, t)
+}
+
+func (p *VenuePremium) expired(v price, t time.Time) bool {
+	age := t.Sub(v.time)
+	if age < 0 {
+		age = -age
+	}
+	return age > time.Duration(p.MaxAge)
+}
+
+func init() {
+	processors.Add("venue_premium", func() telegraf.Processor {
+		return &VenuePremium{
+			AssetTag:   "base",
+			MetricName: "venue_premium",
+			MaxAge:     config.Duration(time.Minute),
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package venue_premium
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func newPlugin() *VenuePremium {
+	return &VenuePremium{
+		AssetTag:   "base",
+		MetricName: "venue_premium",
+		MaxAge:     config.Duration(time.Minute),
+		Domestic: &source{
+			Metric: "upbit",
+			Field:  "price",
+			Tags:   map[string]string{"quote": "KRW"},
+		},
+		Foreign: &source{
+			Metric: "binance",
+			Field:  "price",
+			Tags:   map[string]string{"quote": "USDT"},
+		},
+		Log: testutil.Logger{},
+	}
+}
+
+func TestInitInvalid(t *testing.T) {
+	plugin := newPlugin()
+	plugin.Foreign = nil
+	require.EqualError(t, plugin.Init(), "foreign price source required")
+
+	plugin = newPlugin()
+	plugin.FX = &source{Metric: "exchange_rate"}
+	require.EqualError(t, plugin.Init(), "metric and field of the fx price source required")
+}
+
+func TestPremium(t *testing.T) {
+	plugin := newPlugin()
+	plugin.FX = &source{Metric: "exchange_rate", Field: "rate"}
+	require.NoError(t, plugin.Init())
+
+	now := time.Unix(1741735124, 0)
+	input := []telegraf.Metric{
+		metric.New("exchange_rate", map[string]string{"pair": "USDKRW"}, map[string]interface{}{"rate": 1350.0}, now),
+		metric.New("upbit", map[string]string{"base": "BTC", "quote": "KRW"}, map[string]interface{}{"price": 140000000.0}, now),
+		// Prices in other currencies are ignored
+		metric.New("binance", map[string]string{"base": "BTC", "quote": "EUR"}, map[string]interface{}{"price": 90000.0}, now),
+		metric.New("binance", map[string]string{"base": "BTC", "quote": "USDT"}, map[string]interface{}{"price": 100000.0}, now.Add(time.Second)),
+	}
+	expected := make([]telegraf.Metric, 0, len(input)+1)
+	expected = append(expected, input...)
+	expected = append(expected, metric.New(
+		"venue_premium",
+		map[string]string{"asset": "BTC", "domestic": "upbit", "foreign": "binance"},
+		map[string]interface{}{
+			"domestic_price":  140000000.0,
+			"foreign_price":   100000.0,
+			"fx_rate":         1350.0,
+			"premium_percent": 3.7037037,
+		},
+		now.Add(time.Second),
+	))
+
+	actual := plugin.Apply(input...)
+	testutil.RequireMetricsEqual(t, expected, actual, cmpopts.EquateApprox(0, 1e-6))
+}
+
+func TestPremiumWithoutFX(t *testing.T) {
+	plugin := newPlugin()
+	plugin.Domestic.Tags = map[string]string{"quote": "USDT"}
+	require.NoError(t, plugin.Init())
+
+	now := time.Unix(1741735124, 0)
+	plugin.Apply(metric.New("upbit", map[string]string{"base": "BTC", "quote": "USDT"}, map[string]interface{}{"price": 99000.0}, now))
+	actual := plugin.Apply(metric.New("binance", map[string]string{"base": "BTC", "quote": "USDT"}, map[string]interface{}{"price": 100000.0}, now))
+	require.Len(t, actual, 2)
+	premium, found := actual[1].GetField("premium_percent")
+	require.True(t, found)
+	require.InDelta(t, -1.0, premium, 1e-9)
+	require.False(t, actual[1].HasField("fx_rate"))
+}
+
+func TestStalePrices(t *testing.T) {
+	plugin := newPlugin()
+	plugin.Domestic.Tags = nil
+	plugin.Foreign.Tags = nil
+	require.NoError(t, plugin.Init())
+
+	now := time.Unix(1741735124, 0)
+	plugin.Apply(metric.New("upbit", map[string]string{"base": "BTC"}, map[string]interface{}{"price": 99000.0}, now))
+
+	// Prices too far apart are not joined
+	actual := plugin.Apply(metric.New("binance", map[string]string{"base": "BTC"}, map[string]interface{}{"price": 100000.0}, now.Add(2*time.Minute)))
+	require.Len(t, actual, 1)
+
+	// Prices of different assets are not joined
+	actual = plugin.Apply(metric.New("upbit", map[string]string{"base": "ETH"}, map[string]interface{}{"price": 2000.0}, now.Add(2*time.Minute)))
+	require.Len(t, actual, 1)
+}
@@ -19,9 +19,10 @@ const (
 )
 
 type Entry struct {
-	Level byte
-	Name  string
-	Text  string
+	Level      byte
+	Name       string
+	Text       string
+	Attributes map[string]interface{}
 }
 
 func (e *Entry) String() string {
@@ -30,12 +31,20 @@ func (e *Entry) String() string {
 
 // CaptureLogger defines a logging structure for plugins.
 type CaptureLogger struct {
-	Name     string // Name is the plugin name, will be printed in the `[]`.
-	messages []Entry
+	Name       string // Name is the plugin name, will be printed in the `[]`.
+	messages   []Entry
+	attributes map[string]interface{}
+	parent     *CaptureLogger
 	sync.Mutex
 }
 
 func (l *CaptureLogger) print(msg Entry) {
+	// Derived loggers record their messages in the originating logger
+	if l.parent != nil {
+		l.parent.print(msg)
+		return
+	}
+
 	l.Lock()
 	l.messages = append(l.messages, msg)
 	l.Unlock()
@@ -43,19 +52,44 @@ func (l *CaptureLogger) print(msg Entry) {
 }
 
 func (l *CaptureLogger) logf(level byte, format string, args ...any) {
-	l.print(Entry{level, l.Name, fmt.Sprintf(format, args...)})
+	l.print(Entry{Level: level, Name: l.Name, Text: fmt.Sprintf(format, args...), Attributes: l.attributes})
 }
 
 func (l *CaptureLogger) loga(level byte, args ...any) {
-	l.print(Entry{level, l.Name, fmt.Sprint(args...)})
+	l.print(Entry{Level: level, Name: l.Name, Text: fmt.Sprint(args...), Attributes: l.attributes})
 }
 
 func (*CaptureLogger) Level() telegraf.LogLevel {
 	return telegraf.Trace
 }
 
-// Adding attributes is not supported by the test-logger
-func (*CaptureLogger) AddAttribute(string, interface{}) {}
+// AddAttribute records the attribute with all messages logged afterwards
+func (l *CaptureLogger) AddAttribute(key string, value interface{}) {
+	l.attributes = copyAttributes(l.attributes, key, value)
+}
+
+// WithAttribute returns a logger recording the attribute with all messages
+// logged through it. The messages are collected by the original logger.
+func (l *CaptureLogger) WithAttribute(key string, value interface{}) telegraf.Logger {
+	root := l
+	if l.parent != nil {
+		root = l.parent
+	}
+	return &CaptureLogger{
+		Name:       l.Name,
+		attributes: copyAttributes(l.attributes, key, value),
+		parent:     root,
+	}
+}
+
+func copyAttributes(attributes map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	attrs := make(map[string]interface{}, len(attributes)+1)
+	for k, v := range attributes {
+		attrs[k] = v
+	}
+	attrs[key] = value
+	return attrs
+}
 
 func (l *CaptureLogger) Errorf(format string, args ...interface{}) {
 	l.logf(LevelError, format, args...)